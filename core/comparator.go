@@ -0,0 +1,169 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Body of a response together with its content type
+type Body struct {
+	Content     []byte
+	ContentType string
+}
+
+// Comparator compares the body of candidate response against the body of primary response.
+// Only body differences must be set in the returned description.
+type Comparator interface {
+	Compare(candidate, primary Body) (bool, DifferenceDescription)
+}
+
+// ComparatorFunc adapts a function to Comparator interface
+type ComparatorFunc func(candidate, primary Body) (bool, DifferenceDescription)
+
+// Compare calls f(candidate, primary)
+func (f ComparatorFunc) Compare(candidate, primary Body) (bool, DifferenceDescription) {
+	return f(candidate, primary)
+}
+
+// SchemaComparator is a Comparator of JSON documents, so candidate bodies are validated against the configured JSON Schemas too
+type SchemaComparator interface {
+	Comparator
+	ValidatesSchema() bool
+}
+
+// NoiseDetector detects the noise between primary and secondary bodies and returns primary and candidate bodies without it
+type NoiseDetector interface {
+	RemoveNoise(primary, secondary, candidate Body) ([]byte, []byte, error)
+}
+
+// NoiseDetectorFunc adapts a function to NoiseDetector interface
+type NoiseDetectorFunc func(primary, secondary, candidate Body) ([]byte, []byte, error)
+
+// RemoveNoise calls f(primary, secondary, candidate)
+func (f NoiseDetectorFunc) RemoveNoise(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	return f(primary, secondary, candidate)
+}
+
+// NoiseLearner is a NoiseDetector that expresses noise as JSON pointers, so noise can be learned per endpoint and removed without calling secondaries
+type NoiseLearner interface {
+	NoiseDetector
+	DetectNoisePointers(primary, secondary Body) ([]string, error)
+	RemoveNoisePointers(primary, candidate Body, pointers []string) ([]byte, []byte, error)
+}
+
+// registration of a comparator and its noise detector under a name
+type registration struct {
+	name          string
+	comparator    Comparator
+	noiseDetector NoiseDetector
+}
+
+// validatesSchema returns true if candidate bodies compared by the registered comparator must be validated against JSON Schemas
+func (r registration) validatesSchema() bool {
+	schemaComparator, ok := r.comparator.(SchemaComparator)
+	return ok && schemaComparator.ValidatesSchema()
+}
+
+// mediaTypeBinding binds a media type pattern to a registration name
+type mediaTypeBinding struct {
+	pattern string
+	name    string
+}
+
+var registrations = make(map[string]registration)
+var bindings []mediaTypeBinding
+
+// RegisterComparator registers a comparator and its noise detector under given name for the media types.
+// Media types can be exact (application/json), wildcards (text/* or */*) or suffixes (+json).
+// Registering an already registered name replaces the previous comparator, and when more than one
+// registration matches with the same precision, the last one registered is used.
+func RegisterComparator(name string, comparator Comparator, noiseDetector NoiseDetector, mediaTypes ...string) {
+	registrations[name] = registration{name: name, comparator: comparator, noiseDetector: noiseDetector}
+
+	for _, mediaType := range mediaTypes {
+		bindings = append(bindings, mediaTypeBinding{pattern: strings.ToLower(strings.TrimSpace(mediaType)), name: name})
+	}
+}
+
+// IsComparatorRegistered checks if there is a comparator registered under given name
+func IsComparatorRegistered(name string) bool {
+	_, ok := registrations[name]
+	return ok
+}
+
+// ComparatorsByMediaType returns the comparators chosen for media types defined as mediaType=comparator indexed by media type
+func (conf DiferenciaConfiguration) ComparatorsByMediaType() (map[string]string, error) {
+	comparators := make(map[string]string)
+
+	for _, definition := range conf.Comparators {
+		separator := strings.LastIndex(definition, "=")
+
+		if separator <= 0 || separator == len(definition)-1 {
+			return nil, fmt.Errorf("Comparator definition %s does not follow mediaType=comparator format", definition)
+		}
+
+		name := definition[separator+1:]
+		if !IsComparatorRegistered(name) {
+			return nil, fmt.Errorf("Cannot find %s comparator", name)
+		}
+
+		comparators[strings.ToLower(strings.TrimSpace(definition[:separator]))] = name
+	}
+
+	return comparators, nil
+}
+
+// findComparator returns the registration used for the content type. Comparators chosen in configuration have precedence over registered ones.
+func findComparator(contentType string) (registration, bool) {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	// Comparators format is validated when Diferencia starts
+	comparators, _ := Config.ComparatorsByMediaType()
+	var overrides []mediaTypeBinding
+	for pattern, name := range comparators {
+		overrides = append(overrides, mediaTypeBinding{pattern: pattern, name: name})
+	}
+
+	for _, candidates := range [][]mediaTypeBinding{overrides, bindings} {
+		if name, ok := bestMatch(mediaType, candidates); ok {
+			return registrations[name], true
+		}
+	}
+
+	return registration{}, false
+}
+
+// defaultComparator is used when no comparator is registered for the content type
+func defaultComparator() registration {
+	if Config.ForcePlainText {
+		return registrations["Plain"]
+	}
+	return registrations["Json"]
+}
+
+func bestMatch(mediaType string, candidates []mediaTypeBinding) (string, bool) {
+	name, best := "", -1
+
+	for _, binding := range candidates {
+		if precision := matchPrecision(binding.pattern, mediaType); precision >= 0 && precision >= best {
+			name, best = binding.name, precision
+		}
+	}
+
+	return name, best >= 0
+}
+
+// matchPrecision returns how precisely the pattern matches the media type, from 3 (exact match) to 0 (*/*), or -1 if it does not match
+func matchPrecision(pattern, mediaType string) int {
+	switch {
+	case pattern == mediaType:
+		return 3
+	case strings.HasPrefix(pattern, "+") && strings.HasSuffix(mediaType, pattern):
+		return 2
+	case pattern == "*/*":
+		return 0
+	case strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")):
+		return 1
+	}
+	return -1
+}
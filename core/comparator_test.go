@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/lordofthejars/diferencia/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Comparator Registry", func() {
+
+	// Constant comparator always reports a difference, so it is easy to know when it is used
+	core.RegisterComparator("Constant", core.ComparatorFunc(func(candidate, primary core.Body) (bool, core.DifferenceDescription) {
+		return false, core.DifferenceDescription{BodyDiff: "Constant comparator with " + candidate.ContentType}
+	}), core.NoiseDetectorFunc(func(primary, secondary, candidate core.Body) ([]byte, []byte, error) {
+		return primary.Content, candidate.Content, nil
+	}), "+acme")
+
+	compare := func(contentType string, comparators []string, primary, candidate string) core.Result {
+		var httpClient = &StubHttpClient{}
+		recordContent(httpClient, primary, candidate)
+		recordStatus(httpClient, 200, 200)
+		header := http.Header{}
+		header.Set("Content-Type", contentType)
+		recordHeader(httpClient, header, header)
+		core.HttpClient = httpClient
+
+		core.Config = &core.DiferenciaConfiguration{
+			Port:           8080,
+			Primary:        "http://now.httpbin.org/",
+			Candidate:      "http://now.httpbin.org/",
+			DifferenceMode: core.Strict,
+			Comparators:    comparators,
+		}
+
+		url, _ := url.Parse("http://localhost:8080")
+		request := createRequest(http.MethodGet, url)
+
+		result, _, err := core.Diferencia(&request)
+		Expect(err).Should(Succeed())
+
+		return result
+	}
+
+	Context("Choosing comparators", func() {
+		It("should use registered comparator for media type suffix", func() {
+			result := compare("application/vnd.diferencia+acme; charset=utf-8", nil, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+
+			Expect(result.EqualContent).Should(Equal(false))
+			Expect(result.Diff.BodyDiff).Should(Equal("Constant comparator with application/vnd.diferencia+acme; charset=utf-8"))
+		})
+
+		It("should use comparator set in configuration before registered ones", func() {
+			result := compare("application/json", []string{"application/*=Constant"}, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+
+			Expect(result.EqualContent).Should(Equal(false))
+			Expect(result.Diff.BodyDiff).Should(ContainSubstring("Constant comparator"))
+		})
+
+		It("should use the most precise media type set in configuration", func() {
+			result := compare("application/json", []string{"*/*=Constant", "application/json=Json"}, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+
+			Expect(result.EqualContent).Should(Equal(true))
+		})
+
+		It("should use Json comparator if no comparator is registered for media type", func() {
+			result := compare("application/vnd.diferencia.unknown", nil, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json")
+
+			Expect(result.EqualContent).Should(Equal(false))
+			Expect(result.Diff.BodyPatch).ShouldNot(BeEmpty())
+		})
+	})
+
+	Context("Configuring comparators", func() {
+		It("should return comparators indexed by media type", func() {
+			conf := core.DiferenciaConfiguration{Comparators: []string{"Text/*=Plain", "+json=Json"}}
+
+			comparators, err := conf.ComparatorsByMediaType()
+
+			Expect(err).Should(Succeed())
+			Expect(comparators).Should(Equal(map[string]string{"text/*": "Plain", "+json": "Json"}))
+		})
+
+		It("should fail if definition does not follow mediaType=comparator format", func() {
+			conf := core.DiferenciaConfiguration{Comparators: []string{"text/plain"}}
+
+			_, err := conf.ComparatorsByMediaType()
+
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should fail if comparator is not registered", func() {
+			conf := core.DiferenciaConfiguration{Comparators: []string{"text/csv=Csv"}}
+
+			_, err := conf.ComparatorsByMediaType()
+
+			Expect(err).Should(MatchError("Cannot find Csv comparator"))
+		})
+	})
+})
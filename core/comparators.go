@@ -0,0 +1,344 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lordofthejars/diferencia/difference/binary"
+	"github.com/lordofthejars/diferencia/difference/form"
+	"github.com/lordofthejars/diferencia/difference/html"
+	"github.com/lordofthejars/diferencia/difference/json"
+	"github.com/lordofthejars/diferencia/difference/plain"
+	"github.com/lordofthejars/diferencia/difference/xml"
+	"github.com/lordofthejars/diferencia/difference/yaml"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	RegisterComparator("Json", jsonComparator{}, jsonNoiseDetector{}, "application/json", "+json")
+	RegisterComparator("Xml", ComparatorFunc(compareXml), NoiseDetectorFunc(noiseCancellationXml), "application/xml", "text/xml", "+xml")
+	RegisterComparator("Html", ComparatorFunc(compareHtml), NoiseDetectorFunc(noiseCancellationHtml), "text/html")
+	RegisterComparator("Yaml", ComparatorFunc(compareYaml), NoiseDetectorFunc(noiseCancellationYaml), "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml", "+yaml")
+	RegisterComparator("Form", ComparatorFunc(compareForm), NoiseDetectorFunc(noiseCancellationForm), "application/x-www-form-urlencoded", "multipart/form-data")
+	RegisterComparator("Plain", ComparatorFunc(compareText), NoiseDetectorFunc(noiseCancellationText), "text/plain")
+	RegisterComparator("Binary", ComparatorFunc(compareBinary), NoiseDetectorFunc(noiseCancellationBinary), "application/octet-stream", "application/pdf", "application/zip", "application/gzip", "application/x-gzip", "application/x-tar", "image/*", "audio/*", "video/*", "font/*")
+}
+
+// noiseCancellationText detects and removes noise of texts decoded with their charset, so texts with different charsets are aligned
+// character by character. Texts without noise are encoded again with their charset, since they are decoded again when compared.
+func noiseCancellationText(primary, secondary, candidate Body) ([]byte, []byte, error) {
+
+	primaryCharset, primaryText, primaryDecoded := decodeText(primary)
+	_, secondaryText, _ := decodeText(secondary)
+	candidateCharset, candidateText, candidateDecoded := decodeText(candidate)
+
+	noiseOperation := plain.NoiseOperation{}
+	if err := noiseOperation.Detect(primaryText, secondaryText); err != nil {
+		return nil, nil, err
+	}
+
+	primaryWithoutNoise, candidateWithoutNoise := noiseOperation.Remove(primaryText, candidateText)
+
+	var err error
+	if primaryDecoded {
+		if primaryWithoutNoise, err = plain.EncodeText(primaryWithoutNoise, primaryCharset); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if candidateDecoded {
+		if candidateWithoutNoise, err = plain.EncodeText(candidateWithoutNoise, candidateCharset); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return primaryWithoutNoise, candidateWithoutNoise, nil
+
+}
+
+func noiseCancellationJson(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	noiseOperation := json.NoiseOperation{Options: jsonOptions(), Tolerant: Config.IsTolerantNoiseEnabled()}
+	manualNoise := manualNoiseDetection()
+	noiseOperation.Initialize(manualNoise)
+	err := noiseOperation.Detect(primary.Content, secondary.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return noiseOperation.Remove(primary.Content, candidate.Content)
+}
+
+// jsonNoiseDetector removes noise of JSON documents and it is able to learn noise
+type jsonNoiseDetector struct{}
+
+func (jsonNoiseDetector) RemoveNoise(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	return noiseCancellationJson(primary, secondary, candidate)
+}
+
+func (jsonNoiseDetector) DetectNoisePointers(primary, secondary Body) ([]string, error) {
+	noiseOperation := json.NoiseOperation{Options: jsonOptions(), Tolerant: Config.IsTolerantNoiseEnabled()}
+	err := noiseOperation.Detect(primary.Content, secondary.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pointers are learned in the form of the original documents so they can be exported as ignore values
+	return noiseOperation.OriginalPointers(primary.Content)
+}
+
+// RemoveNoisePointers removes the noise of given pointers and the noise set manually
+func (jsonNoiseDetector) RemoveNoisePointers(primary, candidate Body, pointers []string) ([]byte, []byte, error) {
+	noiseOperation := json.NoiseOperation{Options: jsonOptions(), Tolerant: Config.IsTolerantNoiseEnabled()}
+	noiseOperation.Initialize(append(manualNoiseDetection(), pointers...))
+
+	return noiseOperation.Remove(primary.Content, candidate.Content)
+}
+
+func noiseCancellationXml(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	noiseOperation := xml.NoiseOperation{}
+	noiseOperation.Initialize(Config.IgnoreXPaths)
+	err := noiseOperation.Detect(primary.Content, secondary.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return noiseOperation.Remove(primary.Content, candidate.Content)
+}
+
+func noiseCancellationHtml(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	noiseOperation := html.NoiseOperation{}
+	noiseOperation.Initialize(Config.IgnoreSelectors)
+	err := noiseOperation.Detect(primary.Content, secondary.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return noiseOperation.Remove(primary.Content, candidate.Content)
+}
+
+// noiseCancellationYaml returns primary and candidate as JSON documents without noise
+func noiseCancellationYaml(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	primaryJson, err := yaml.ToJson(primary.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secondaryJson, err := yaml.ToJson(secondary.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateJson, err := yaml.ToJson(candidate.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return noiseCancellationJson(Body{Content: primaryJson}, Body{Content: secondaryJson}, Body{Content: candidateJson})
+}
+
+func noiseCancellationForm(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	noiseOperation := form.NoiseOperation{}
+	err := noiseOperation.Detect(primary.Content, secondary.Content, primary.ContentType, secondary.ContentType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return noiseOperation.Remove(primary.Content, candidate.Content, primary.ContentType, candidate.ContentType)
+}
+
+// noiseCancellationBinary fails if primary and secondary are different since noise cannot be located in binary documents
+func noiseCancellationBinary(primary, secondary, candidate Body) ([]byte, []byte, error) {
+	equal, diff := binary.CompareDocuments(secondary.Content, primary.Content)
+	if !equal {
+		return nil, nil, fmt.Errorf("Primary and Secondary binary payloads are different and noise cannot be removed %s", strings.Replace(diff, "\n", " ", -1))
+	}
+
+	return primary.Content, candidate.Content, nil
+}
+
+// jsonComparator compares JSON documents whose candidate is validated against JSON Schemas
+type jsonComparator struct{}
+
+func (jsonComparator) Compare(candidate, primary Body) (bool, DifferenceDescription) {
+	return compareJson(candidate, primary)
+}
+
+func (jsonComparator) ValidatesSchema() bool {
+	return true
+}
+
+func compareJson(candidate, primary Body) (bool, DifferenceDescription) {
+
+	bodyEqual, bodyDiff := true, ""
+
+	// In JsonSchema mode, the body is validated against the schema instead of being compared to primary
+	if Config.DifferenceMode != JsonSchema {
+		bodyEqual, bodyDiff = json.CompareDocumentsWithOptions(candidate.Content, primary.Content, Config.DifferenceMode.String(), jsonOptions())
+	}
+
+	description := DifferenceDescription{}
+
+	if !bodyEqual {
+		if Config.IsTextBodyDiffEnabled() {
+			description.BodyDiff = bodyDiff
+		}
+
+		if Config.IsPatchBodyDiffEnabled() {
+			bodyPatch, err := json.DiffDocumentsWithOptions(candidate.Content, primary.Content, Config.DifferenceMode.String(), jsonOptions())
+			if err != nil {
+				logrus.WithError(err).Debugf("Body differences cannot be expressed as JSON Patch.")
+				// Text form is the only way to report differences of invalid documents
+				description.BodyDiff = bodyDiff
+			}
+			description.BodyPatch = bodyPatch
+		}
+	}
+
+	return bodyEqual, description
+}
+
+func compareXml(candidate, primary Body) (bool, DifferenceDescription) {
+	bodyEqual, bodyDiff := xml.CompareDocumentsIgnoring(candidate.Content, primary.Content, Config.DifferenceMode.String(), Config.IgnoreXPaths)
+	return bodyEqual, DifferenceDescription{BodyDiff: bodyDiff}
+}
+
+func compareHtml(candidate, primary Body) (bool, DifferenceDescription) {
+	bodyEqual, bodyDiff := html.CompareDocumentsIgnoring(candidate.Content, primary.Content, Config.DifferenceMode.String(), Config.IgnoreSelectors)
+	return bodyEqual, DifferenceDescription{BodyDiff: bodyDiff}
+}
+
+// matchValues validates candidate values against value matchers before noise cancellation modifies them, so they are validated only once
+// and as they were received. It returns the candidate where matching values are replaced by the primary ones, and the values not matching.
+// YAML documents are validated as JSON documents, so the returned candidate is a JSON document.
+func matchValues(comparator registration, primary, candidate []byte) ([]byte, string) {
+
+	// Array keys and value matchers format is validated when Diferencia starts
+	arrayKeys, _ := Config.ArrayKeysByPointer()
+	valueMatchers, _ := Config.ValueMatchersByPointer()
+
+	if len(valueMatchers) == 0 {
+		return candidate, ""
+	}
+
+	candidateJson, primaryJson := candidate, primary
+	switch comparator.name {
+	case "Json":
+	case "Yaml":
+		var candidateErr, primaryErr error
+		candidateJson, candidateErr = yaml.ToJson(candidate)
+		primaryJson, primaryErr = yaml.ToJson(primary)
+		if candidateErr != nil || primaryErr != nil {
+			// Invalid documents are reported by the comparator
+			return candidate, ""
+		}
+	default:
+		return candidate, ""
+	}
+
+	matched, mismatches, err := json.MatchValues(candidateJson, primaryJson, json.Options{ArrayKeys: arrayKeys, ValueMatchers: valueMatchers})
+	if err != nil {
+		// Invalid documents are reported by the comparator
+		return candidate, ""
+	}
+
+	return matched, strings.Join(mismatches, "\n")
+}
+
+// compareYaml compares YAML documents as JSON documents, so all JSON options are supported
+func compareYaml(candidate, primary Body) (bool, DifferenceDescription) {
+
+	candidateJson, err := yaml.ToJson(candidate.Content)
+	if err != nil {
+		return false, DifferenceDescription{BodyDiff: fmt.Sprintf("first argument is invalid yaml. %s", err.Error())}
+	}
+
+	primaryJson, err := yaml.ToJson(primary.Content)
+	if err != nil {
+		return false, DifferenceDescription{BodyDiff: fmt.Sprintf("second argument is invalid yaml. %s", err.Error())}
+	}
+
+	return compareJson(Body{Content: candidateJson}, Body{Content: primaryJson})
+}
+
+func compareForm(candidate, primary Body) (bool, DifferenceDescription) {
+	bodyEqual, bodyDiff := form.CompareDocuments(candidate.Content, primary.Content, candidate.ContentType, primary.ContentType, Config.DifferenceMode.String())
+	return bodyEqual, DifferenceDescription{BodyDiff: bodyDiff}
+}
+
+// compareText compares texts after decoding them using the charset of their content type and masking the ignored text patterns.
+// Only decoded texts are compared, so different charsets are reported as information but do not make the comparison fail.
+// Differences between texts are reported in unified diff format.
+func compareText(candidate, primary Body) (bool, DifferenceDescription) {
+	description := DifferenceDescription{}
+
+	primaryCharset, primaryText, _ := decodeText(primary)
+	candidateCharset, candidateText, _ := decodeText(candidate)
+
+	patterns := Config.ignoreTextPatterns
+	primaryText = plain.Mask(primaryText, patterns)
+	candidateText = plain.Mask(candidateText, patterns)
+
+	if primaryCharset != candidateCharset {
+		description.CharsetDiff = fmt.Sprintf(`"charset": %q => %q`, primaryCharset, candidateCharset)
+	}
+
+	// Text similarity is validated when Diferencia starts
+	algorithm, percentage, _ := Config.GetTextSimilarity()
+
+	if percentage < 100 {
+		var dif int
+		qualifier := ""
+
+		if algorithm == plain.LevenshteinSimilarity || algorithm == plain.WordLevenshteinSimilarity {
+			// Levenshtein stops as soon as texts cannot be similar enough, so only the maximum similarity is known
+			similarityAbove := plain.CalculateSimilarityAbove
+			if algorithm == plain.WordLevenshteinSimilarity {
+				similarityAbove = plain.WordLevenshteinAbove
+			}
+
+			result, above := similarityAbove(primaryText, candidateText, percentage)
+			if above {
+				return true, description
+			}
+			dif, qualifier = int(result*100), " or less"
+		} else {
+			similarity, _ := plain.NewSimilarity(algorithm)
+			dif = int(similarity(primaryText, candidateText) * 100)
+			if dif > percentage {
+				return true, description
+			}
+		}
+
+		description.BodyDiff = fmt.Sprintf("%s similarity: %d%%%s (more than %d%% required)\n%s", algorithm, dif, qualifier, percentage, plain.UnifiedDiff(candidateText, primaryText, plain.DefaultContextLines))
+		return false, description
+	}
+
+	if bytes.Equal(candidateText, primaryText) {
+		return true, description
+	}
+
+	description.BodyDiff = plain.UnifiedDiff(candidateText, primaryText, plain.DefaultContextLines)
+	return false, description
+}
+
+// decodeText returns the charset and the UTF-8 content of the body, and if it has been decoded. If body cannot be decoded, content is returned as it is.
+func decodeText(body Body) (string, []byte, bool) {
+	charset, err := plain.Charset(body.ContentType)
+	if err != nil {
+		logrus.WithError(err).Warnf("Text is going to be compared as it is.")
+		return charset, body.Content, false
+	}
+
+	text, err := plain.DecodeText(body.Content, charset)
+	if err != nil {
+		logrus.WithError(err).Warnf("Text cannot be decoded from %s and it is going to be compared as it is.", charset)
+		return charset, body.Content, false
+	}
+
+	return charset, text, true
+}
+
+func compareBinary(candidate, primary Body) (bool, DifferenceDescription) {
+	bodyEqual, bodyDiff := binary.CompareDocuments(candidate.Content, primary.Content)
+	return bodyEqual, DifferenceDescription{BodyDiff: bodyDiff}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/sirupsen/logrus"
+)
+
+// contentEncodings returns the encodings set in Content-Encoding header in the order they were applied. Identity encoding is not returned.
+func contentEncodings(header http.Header) []string {
+	var encodings []string
+
+	for _, value := range header[http.CanonicalHeaderKey("Content-Encoding")] {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if len(encoding) > 0 && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+
+	return encodings
+}
+
+// contentTooLargeError is returned when decoded content is larger than the maximum allowed, to protect against decompression bombs
+type contentTooLargeError struct {
+	maxSize int64
+}
+
+func (e contentTooLargeError) Error() string {
+	return fmt.Sprintf("Decoded content is larger than the maximum of %d bytes", e.maxSize)
+}
+
+// decodeContent decompresses content with gzip, deflate or br encodings. Since encodings are applied in order, they are decoded in reverse order.
+// Decoding fails with contentTooLargeError as soon as any decoded content is larger than maxSize bytes.
+func decodeContent(content []byte, encodings []string, maxSize int64) ([]byte, error) {
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var reader io.Reader
+		var err error
+
+		switch encodings[i] {
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(content))
+		case "deflate":
+			// deflate should be zlib format but some servers send raw deflate
+			reader, err = zlib.NewReader(bytes.NewReader(content))
+			if err != nil {
+				reader, err = flate.NewReader(bytes.NewReader(content)), nil
+			}
+		case "br":
+			reader = brotli.NewReader(bytes.NewReader(content))
+		default:
+			return nil, fmt.Errorf("Content encoding %s is not supported", encodings[i])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// One more byte than the maximum is read to know if content is larger than the maximum
+		content, err = ioutil.ReadAll(io.LimitReader(reader, maxSize+1))
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(len(content)) > maxSize {
+			return nil, contentTooLargeError{maxSize: maxSize}
+		}
+	}
+
+	return content, nil
+}
+
+// decodeResponse returns the decoded content of the response so responses with different encodings can be compared.
+// If content cannot be decoded, the content is returned as it is, but if decoded content is too large an error is returned.
+func decodeResponse(content []byte, header http.Header, url string) ([]byte, error) {
+	encodings := contentEncodings(header)
+
+	if len(encodings) == 0 {
+		return content, nil
+	}
+
+	decoded, err := decodeContent(content, encodings, Config.GetMaxDecodedBodySize())
+	if _, tooLarge := err.(contentTooLargeError); tooLarge {
+		return nil, fmt.Errorf("Content of %s cannot be decoded with %v encoding. %s", url, encodings, err.Error())
+	}
+
+	if err != nil {
+		logrus.WithError(err).Warnf("Content of %s cannot be decoded with %v encoding and it is going to be compared as it is.", url, encodings)
+		return content, nil
+	}
+
+	return decoded, nil
+}
+
+// compareContentEncoding compares the Content-Encoding of the responses and returns the difference in the same format as headers differences
+func compareContentEncoding(candidateHeader, primaryHeader http.Header) (bool, string) {
+	primaryEncodings := contentEncodings(primaryHeader)
+	candidateEncodings := contentEncodings(candidateHeader)
+
+	if strings.Join(primaryEncodings, ",") == strings.Join(candidateEncodings, ",") {
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("Content-Encoding:%v => %v", primaryEncodings, candidateEncodings)
+}
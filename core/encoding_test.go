@@ -0,0 +1,144 @@
+package core_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/lordofthejars/diferencia/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Content Encoding", func() {
+
+	encode := func(encoding string, content string) string {
+		var b bytes.Buffer
+		switch encoding {
+		case "gzip":
+			w := gzip.NewWriter(&b)
+			w.Write([]byte(content))
+			w.Close()
+		case "br":
+			w := brotli.NewWriter(&b)
+			w.Write([]byte(content))
+			w.Close()
+		default:
+			b.WriteString(content)
+		}
+		return b.String()
+	}
+
+	header := func(encoding string) http.Header {
+		h := http.Header{}
+		h.Set("Content-Type", "application/json")
+		if len(encoding) > 0 {
+			h.Set("Content-Encoding", encoding)
+		}
+		return h
+	}
+
+	configure := func(compareContentEncoding, mirroring bool) {
+		core.Config = &core.DiferenciaConfiguration{
+			Port:                   8080,
+			Primary:                "http://now.httpbin.org/",
+			Candidate:              "http://now.httpbin.org/",
+			DifferenceMode:         core.Strict,
+			Mirroring:              mirroring,
+			CompareContentEncoding: compareContentEncoding,
+			MaxDecodedBodySize:     1,
+		}
+	}
+
+	document := loadFromFile("test_fixtures/document-a.json")
+
+	It("should decode gzip and br responses before comparing them", func() {
+		// Given
+		var httpClient = &StubHttpClient{}
+		httpClient.content = []string{encode("gzip", document), encode("br", document)}
+		recordStatus(httpClient, 200, 200)
+		recordHeader(httpClient, header("gzip"), header("br"))
+		core.HttpClient = httpClient
+		configure(false, true)
+
+		url, _ := url.Parse("http://localhost:8080")
+		request := createRequest(http.MethodGet, url)
+
+		// When
+		result, primary, err := core.Diferencia(&request)
+
+		// Then
+		Expect(err).Should(Succeed())
+		Expect(result.EqualContent).Should(Equal(true))
+		// Mirroring returns primary content as it was received
+		Expect(string(primary.Content)).Should(Equal(encode("gzip", document)))
+	})
+
+	It("should compare encoded response with identity one", func() {
+		// Given
+		var httpClient = &StubHttpClient{}
+		httpClient.content = []string{encode("gzip", document), document}
+		recordStatus(httpClient, 200, 200)
+		recordHeader(httpClient, header("gzip"), header("identity"))
+		core.HttpClient = httpClient
+		configure(false, false)
+
+		url, _ := url.Parse("http://localhost:8080")
+		request := createRequest(http.MethodGet, url)
+
+		// When
+		result, _, err := core.Diferencia(&request)
+
+		// Then
+		Expect(err).Should(Succeed())
+		Expect(result.EqualContent).Should(Equal(true))
+	})
+
+	It("should report different Content-Encoding as headers difference if enabled", func() {
+		// Given
+		var httpClient = &StubHttpClient{}
+		httpClient.content = []string{encode("gzip", document), encode("br", document)}
+		recordStatus(httpClient, 200, 200)
+		recordHeader(httpClient, header("gzip"), header("br"))
+		core.HttpClient = httpClient
+		configure(true, false)
+
+		url, _ := url.Parse("http://localhost:8080")
+		request := createRequest(http.MethodGet, url)
+
+		// When
+		result, _, err := core.Diferencia(&request)
+
+		// Then
+		Expect(err).Should(Succeed())
+		Expect(result.EqualContent).Should(Equal(false))
+		Expect(result.Diff.HeadersDiff).Should(Equal("Content-Encoding:[gzip] => [br]"))
+		Expect(result.Diff.BodyDiff).Should(BeEmpty())
+	})
+
+	It("should fail if decoded response is larger than the maximum", func() {
+		// Given
+		bomb := encode("gzip", strings.Repeat("0", 2<<20))
+		var httpClient = &StubHttpClient{}
+		httpClient.content = []string{bomb, bomb}
+		recordStatus(httpClient, 200, 200)
+		recordHeader(httpClient, header("gzip"), header("gzip"))
+		core.HttpClient = httpClient
+		configure(false, false)
+
+		url, _ := url.Parse("http://localhost:8080")
+		request := createRequest(http.MethodGet, url)
+
+		// When
+		result, _, err := core.Diferencia(&request)
+
+		// Then
+		Expect(len(bomb)).Should(BeNumerically("<", 10<<10))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("larger than the maximum of 1048576 bytes"))
+		Expect(result.EqualContent).Should(Equal(false))
+	})
+})
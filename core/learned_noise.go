@@ -0,0 +1,265 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lordofthejars/diferencia/exporter"
+)
+
+// LearnedNoise contains the noise pointers learned for an endpoint and how many times each one has been detected
+type LearnedNoise struct {
+	Endpoint               exporter.URLCall `json:"endpoint"`
+	Detections             int              `json:"detections"`
+	StableDetections       int              `json:"stableDetections"`
+	RequestsSinceDetection int              `json:"requestsSinceDetection"`
+	Pointers               map[string]int   `json:"pointers"`
+}
+
+// isStable returns true if the last detections have not found new noise
+func (noise LearnedNoise) isStable() bool {
+	return noise.StableDetections >= Config.NoiseStableDetections
+}
+
+func (noise LearnedNoise) pointers() []string {
+	var pointers []string
+	for pointer := range noise.Pointers {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+
+	return pointers
+}
+
+// learnedNoiseMap is a concurrent map storing for each endpoint the noise learned
+type learnedNoiseMap struct {
+	sync.RWMutex
+	internal map[exporter.URLCall]LearnedNoise
+}
+
+func newLearnedNoiseMap() *learnedNoiseMap {
+	return &learnedNoiseMap{
+		internal: make(map[exporter.URLCall]LearnedNoise),
+	}
+}
+
+func (m *learnedNoiseMap) get(method, path string) (LearnedNoise, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	noise, ok := m.internal[exporter.URLCall{Method: method, Path: path}]
+	return noise, ok
+}
+
+// requiresSecondaries returns true if noise of the endpoint is not stable yet or it is time to check it again
+func (m *learnedNoiseMap) requiresSecondaries(method, path string) bool {
+	noise, ok := m.get(method, path)
+	if !ok || !noise.isStable() {
+		return true
+	}
+
+	return noise.RequestsSinceDetection+1 >= Config.NoiseRecheckInterval
+}
+
+// learn adds the detected pointers to the endpoint. Detections without new pointers make learned noise more stable.
+func (m *learnedNoiseMap) learn(method, path string, pointers []string) LearnedNoise {
+	m.Lock()
+	defer m.Unlock()
+	call := exporter.URLCall{Method: method, Path: path}
+
+	noise, ok := m.internal[call]
+	if !ok {
+		noise = LearnedNoise{Endpoint: call, Pointers: make(map[string]int)}
+	}
+
+	newPointers := false
+	for _, pointer := range pointers {
+		if _, found := noise.Pointers[pointer]; !found {
+			newPointers = true
+		}
+		noise.Pointers[pointer]++
+	}
+
+	noise.Detections++
+	noise.RequestsSinceDetection = 0
+	if newPointers {
+		noise.StableDetections = 0
+	} else {
+		noise.StableDetections++
+	}
+
+	m.internal[call] = noise
+	return noise
+}
+
+// skip registers a request where learned noise has been used without calling secondaries
+func (m *learnedNoiseMap) skip(method, path string) LearnedNoise {
+	m.Lock()
+	defer m.Unlock()
+	call := exporter.URLCall{Method: method, Path: path}
+
+	noise := m.internal[call]
+	noise.RequestsSinceDetection++
+	m.internal[call] = noise
+
+	return noise
+}
+
+// entries returns learned noise sorted by path and method
+func (m *learnedNoiseMap) entries() []LearnedNoise {
+	m.RLock()
+	defer m.RUnlock()
+
+	entries := make([]LearnedNoise, 0, len(m.internal))
+	for _, noise := range m.internal {
+		entries = append(entries, noise)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Endpoint.Path != entries[j].Endpoint.Path {
+			return entries[i].Endpoint.Path < entries[j].Endpoint.Path
+		}
+		return entries[i].Endpoint.Method < entries[j].Endpoint.Method
+	})
+
+	return entries
+}
+
+func (m *learnedNoiseMap) replace(entries []LearnedNoise) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.internal = make(map[exporter.URLCall]LearnedNoise)
+	for _, noise := range entries {
+		if noise.Pointers == nil {
+			noise.Pointers = make(map[string]int)
+		}
+		m.internal[noise.Endpoint] = noise
+	}
+}
+
+var learnedNoise = newLearnedNoiseMap()
+
+// LearnedNoises returns the noise learned for every endpoint
+func LearnedNoises() []LearnedNoise {
+	return learnedNoise.entries()
+}
+
+// UpdateLearnedNoises replaces all learned noise by the given one
+func UpdateLearnedNoises(entries []LearnedNoise) {
+	learnedNoise.replace(entries)
+}
+
+// ResetLearnedNoise removes all learned noise
+func ResetLearnedNoise() {
+	learnedNoise.replace(nil)
+}
+
+// ExportLearnedNoise returns the learned pointers, one per line, with the format of the ignore values file.
+// If method and path are not empty, only pointers of this endpoint are exported.
+func ExportLearnedNoise(method, path string) string {
+	pointers := make(map[string]bool)
+	for _, noise := range learnedNoise.entries() {
+		if len(method) > 0 && noise.Endpoint.Method != method {
+			continue
+		}
+		if len(path) > 0 && noise.Endpoint.Path != path {
+			continue
+		}
+
+		for pointer := range noise.Pointers {
+			pointers[pointer] = true
+		}
+	}
+
+	var lines []string
+	for pointer := range pointers {
+		lines = append(lines, pointer)
+	}
+	sort.Strings(lines)
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// learnNoise removes the noise learned for the endpoint. Secondaries are only called while noise is not stable or when it must be checked again,
+// and the noise detected with them is added to the learned one.
+func learnNoise(r *http.Request, learner NoiseLearner, primaryFullURL string, primaryStatus int, primary, candidate Body) ([]byte, []byte, []secondaryResponse, *DiferenciaError) {
+
+	var secondaries []secondaryResponse
+	var noise LearnedNoise
+
+	if learnedNoise.requiresSecondaries(r.Method, r.URL.Path) {
+		var diferenciaError *DiferenciaError
+		secondaries, diferenciaError = getSecondaries(r, primaryFullURL, primaryStatus)
+		if diferenciaError != nil {
+			return nil, nil, nil, diferenciaError
+		}
+
+		var detected []string
+		for _, secondary := range secondaries {
+			pointers, err := learner.DetectNoisePointers(primary, Body{Content: secondary.content, ContentType: responseContentType(secondary.header, primary.ContentType)})
+			if err != nil {
+				return nil, nil, nil, noiseError(primaryFullURL, secondaries, err)
+			}
+			detected = append(detected, pointers...)
+		}
+
+		noise = learnedNoise.learn(r.Method, r.URL.Path, detected)
+	} else {
+		noise = learnedNoise.skip(r.Method, r.URL.Path)
+	}
+
+	primaryWithoutNoise, candidateWithoutNoise, err := learner.RemoveNoisePointers(primary, candidate, noise.pointers())
+	if err != nil {
+		return nil, nil, nil, noiseError(primaryFullURL, secondaries, err)
+	}
+
+	return primaryWithoutNoise, candidateWithoutNoise, secondaries, nil
+}
+
+func learnedNoiseHandler(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(LearnedNoises())
+	case http.MethodPut:
+		var entries []LearnedNoise
+
+		if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, err.Error())
+			return
+		}
+
+		UpdateLearnedNoises(entries)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		ResetLearnedNoise()
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+}
+
+func learnedNoiseExportHandler(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ExportLearnedNoise(r.URL.Query().Get("method"), r.URL.Query().Get("path")))
+
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// secondaryResponse is the response of one of the secondary services used to detect noise
+type secondaryResponse struct {
+	url     string
+	content []byte
+	status  int
+	header  http.Header
+}
+
+// getSecondaries requests all secondary services and returns the responses that can be used to detect noise, which are the ones with the same status code as primary.
+// Unavailable secondaries are skipped, but if less responses than the quorum are available, the error of the last unavailable secondary is returned.
+func getSecondaries(r *http.Request, primaryFullURL string, primaryStatus int) ([]secondaryResponse, *DiferenciaError) {
+	var responses []secondaryResponse
+	var lastError *DiferenciaError
+
+	for _, secondary := range Config.Secondaries() {
+		secondaryFullURL := CreateUrl(*r.URL, secondary)
+		logrus.Debugf("Forwarding call to %s", secondaryFullURL)
+		secondaryBodyContent, secondaryStatus, secondaryHeader, _, err := getContent(r, secondaryFullURL)
+		if err != nil {
+			logrus.Errorf("Error while connecting to Secondary site (%s) with error %s", secondaryFullURL, err.Error())
+			lastError = &DiferenciaError{http.StatusServiceUnavailable, fmt.Sprintf("Error while connecting to Secondary site (%s) with error %s", secondaryFullURL, err.Error())}
+			continue
+		}
+
+		// Only responses with same status code are used to detect noise
+		// What to do in case of two identical status code but no body content (404) might be still valid since you are testing that nothing is there
+		if primaryStatus != secondaryStatus {
+			logrus.Errorf("Status code between %s(%d) and %s(%d) are different", primaryFullURL, primaryStatus, secondaryFullURL, secondaryStatus)
+			lastError = &DiferenciaError{http.StatusBadRequest, fmt.Sprintf("Status code between %s(%d) and %s(%d) are different", primaryFullURL, primaryStatus, secondaryFullURL, secondaryStatus)}
+			continue
+		}
+
+		secondaryBodyContent, err = decodeResponse(secondaryBodyContent, secondaryHeader, secondaryFullURL)
+		if err != nil {
+			logrus.Errorf("%s", err.Error())
+			lastError = &DiferenciaError{http.StatusBadGateway, err.Error()}
+			continue
+		}
+
+		responses = append(responses, secondaryResponse{
+			url:     secondaryFullURL,
+			content: secondaryBodyContent,
+			status:  secondaryStatus,
+			header:  secondaryHeader,
+		})
+	}
+
+	if len(responses) < Config.GetSecondaryQuorum() {
+		logrus.Errorf("Only %d secondaries of %d required are available to detect noise", len(responses), Config.GetSecondaryQuorum())
+		return nil, lastError
+	}
+
+	return responses, nil
+}
+
+// detectNoise requests all secondaries and returns primary and candidate without the noise detected between primary and each secondary
+func detectNoise(r *http.Request, noiseDetector NoiseDetector, primaryFullURL string, primaryStatus int, primary, candidate Body) ([]byte, []byte, []secondaryResponse, *DiferenciaError) {
+	secondaries, diferenciaError := getSecondaries(r, primaryFullURL, primaryStatus)
+	if diferenciaError != nil {
+		return nil, nil, nil, diferenciaError
+	}
+
+	primaryWithoutNoise, candidateWithoutNoise, err := removeNoise(noiseDetector, primary, candidate, secondaries)
+	if err != nil {
+		return nil, nil, nil, noiseError(primaryFullURL, secondaries, err)
+	}
+
+	return primaryWithoutNoise, candidateWithoutNoise, secondaries, nil
+}
+
+// removeNoise removes from primary and candidate the union of the noise detected between primary and each secondary.
+// Noise detected with one secondary is removed from the next secondaries too, so the next ones are compared with primary without this noise.
+func removeNoise(noiseDetector NoiseDetector, primary, candidate Body, secondaries []secondaryResponse) ([]byte, []byte, error) {
+
+	secondaryBodies := make([]Body, len(secondaries))
+	for i, secondary := range secondaries {
+		secondaryBodies[i] = Body{Content: secondary.content, ContentType: responseContentType(secondary.header, primary.ContentType)}
+	}
+
+	for i, secondary := range secondaryBodies {
+		for j := i + 1; j < len(secondaryBodies); j++ {
+			_, secondaryWithoutNoise, err := noiseDetector.RemoveNoise(primary, secondary, secondaryBodies[j])
+			if err != nil {
+				return nil, nil, err
+			}
+			secondaryBodies[j].Content = secondaryWithoutNoise
+		}
+
+		primaryWithoutNoise, candidateWithoutNoise, err := noiseDetector.RemoveNoise(primary, secondary, candidate)
+		if err != nil {
+			return nil, nil, err
+		}
+		primary.Content, candidate.Content = primaryWithoutNoise, candidateWithoutNoise
+	}
+
+	return primary.Content, candidate.Content, nil
+}
+
+func noiseError(primaryFullURL string, secondaries []secondaryResponse, err error) *DiferenciaError {
+	var urls []string
+	for _, secondary := range secondaries {
+		urls = append(urls, secondary.url)
+	}
+	secondaryURLs := strings.Join(urls, ", ")
+
+	logrus.WithError(err).Errorf("Error detecting noise between %s and %s.", primaryFullURL, secondaryURLs)
+	return &DiferenciaError{http.StatusBadRequest, fmt.Sprintf("Error detecting noise between %s and %s. (%s)", primaryFullURL, secondaryURLs, err.Error())}
+}
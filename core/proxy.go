@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -16,7 +17,9 @@ import (
 	"github.com/lordofthejars/diferencia/difference/header"
 	"github.com/lordofthejars/diferencia/difference/plain"
 
+	"github.com/lordofthejars/diferencia/difference/html"
 	"github.com/lordofthejars/diferencia/difference/json"
+	"github.com/lordofthejars/diferencia/difference/xml"
 	"github.com/lordofthejars/diferencia/exporter"
 	"github.com/lordofthejars/diferencia/metrics"
 	"github.com/prometheus/client_golang/prometheus"
@@ -31,9 +34,10 @@ func (difference Difference) String() string {
 	names := [...]string{
 		"Strict",
 		"Subset",
-		"Schema"}
+		"Schema",
+		"JsonSchema"}
 
-	if difference < Strict || difference > Schema {
+	if difference < Strict || difference > JsonSchema {
 		return "Unknown"
 	}
 	return names[difference]
@@ -49,6 +53,8 @@ func NewDifference(difference string) (Difference, error) {
 		return Subset, nil
 	case "Schema":
 		return Schema, nil
+	case "JsonSchema":
+		return JsonSchema, nil
 	}
 
 	return -1, fmt.Errorf("Cannot find %s difference mode", difference)
@@ -71,34 +77,62 @@ const (
 	Subset Difference = 1
 	// Schema mode where the schema must be equal but not the values
 	Schema Difference = 2
+	// JsonSchema mode where the candidate must be valid against the configured JSON Schema instead of being compared to primary
+	JsonSchema Difference = 3
 )
 
 // DiferenciaConfiguration object
 type DiferenciaConfiguration struct {
-	Port                  int        `json:"port,omitempty"`
-	ServiceName           string     `json:"serviceName,omitempty"`
-	Primary               string     `json:"primary,omitempty"`
-	Secondary             string     `json:"secondary,omitempty"`
-	Candidate             string     `json:"candidate,omitempty"`
-	StoreResults          string     `json:"storeResults,omitempty"`
-	DifferenceMode        Difference `json:"-"`
-	NoiseDetection        bool       `json:"noiseDetection,omitempty"`
-	AllowUnsafeOperations bool       `json:"allowUnsafeOperartions,omitempty"`
-	Prometheus            bool       `json:"prometheus,omitempty"`
-	PrometheusPort        int        `json:"prometheusPort,omitempty"`
-	Headers               bool       `json:"headers,omitempty"`
-	IgnoreHeadersValues   []string   `json:"ignoreHeadersValues,omitempty"`
-	IgnoreValues          []string   `json:"ignoreValues,omitempty"`
-	IgnoreValuesFile      string     `json:"ignoreValuesFile,omitempty"`
-	InsecureSkipVerify    bool       `json:"insecureSkipVerify,omitempty"`
-	CaCert                string     `json:"caCert,omitempty"`
-	ClientCert            string     `json:"clientCert,omitempty"`
-	ClientKey             string     `json:"clientKey,omitempty"`
-	AdminPort             int        `json:"adminPort,omitempty"`
-	ForcePlainText        bool       `json:"forcePlainText,omitempty"`
-	LevenshteinPercentage int        `json:"levenshteinPercentage,omitempty"`
-	Mirroring             bool       `json:"mirroring,omitempty"`
-	ReturnResult          bool       `json:"returnResult,omitempty"`
+	Port                   int        `json:"port,omitempty"`
+	ServiceName            string     `json:"serviceName,omitempty"`
+	Primary                string     `json:"primary,omitempty"`
+	Secondary              string     `json:"secondary,omitempty"`
+	Candidate              string     `json:"candidate,omitempty"`
+	StoreResults           string     `json:"storeResults,omitempty"`
+	DifferenceMode         Difference `json:"-"`
+	NoiseDetection         bool       `json:"noiseDetection,omitempty"`
+	AllowUnsafeOperations  bool       `json:"allowUnsafeOperartions,omitempty"`
+	Prometheus             bool       `json:"prometheus,omitempty"`
+	PrometheusPort         int        `json:"prometheusPort,omitempty"`
+	Headers                bool       `json:"headers,omitempty"`
+	IgnoreHeadersValues    []string   `json:"ignoreHeadersValues,omitempty"`
+	IgnoreValues           []string   `json:"ignoreValues,omitempty"`
+	IgnoreValuesFile       string     `json:"ignoreValuesFile,omitempty"`
+	InsecureSkipVerify     bool       `json:"insecureSkipVerify,omitempty"`
+	CaCert                 string     `json:"caCert,omitempty"`
+	ClientCert             string     `json:"clientCert,omitempty"`
+	ClientKey              string     `json:"clientKey,omitempty"`
+	AdminPort              int        `json:"adminPort,omitempty"`
+	ForcePlainText         bool       `json:"forcePlainText,omitempty"`
+	LevenshteinPercentage  int        `json:"levenshteinPercentage,omitempty"`
+	Mirroring              bool       `json:"mirroring,omitempty"`
+	ReturnResult           bool       `json:"returnResult,omitempty"`
+	JsonSchema             string     `json:"jsonSchema,omitempty"`
+	JsonSchemas            []string   `json:"jsonSchemas,omitempty"`
+	UnorderedArrays        []string   `json:"unorderedArrays,omitempty"`
+	ArrayKeys              []string   `json:"arrayKeys,omitempty"`
+	NumericTolerances      []string   `json:"numericTolerances,omitempty"`
+	DefaultTolerance       string     `json:"defaultTolerance,omitempty"`
+	BodyDiffFormat         string     `json:"bodyDiffFormat,omitempty"`
+	IgnoreXPaths           []string   `json:"ignoreXPaths,omitempty"`
+	IgnoreSelectors        []string   `json:"ignoreSelectors,omitempty"`
+	Comparators            []string   `json:"comparators,omitempty"`
+	CompareContentEncoding bool       `json:"compareContentEncoding,omitempty"`
+	MaxDecodedBodySize     int        `json:"maxDecodedBodySize,omitempty"`
+	TextSimilarity         string     `json:"textSimilarity,omitempty"`
+	IgnoreTextPatterns     []string   `json:"ignoreTextPatterns,omitempty"`
+	IgnoreTextPatternsFile string     `json:"ignoreTextPatternsFile,omitempty"`
+	ValueMatchers          []string   `json:"valueMatchers,omitempty"`
+	SecondaryQuorum        int        `json:"secondaryQuorum,omitempty"`
+	LearnNoise             bool       `json:"learnNoise,omitempty"`
+	NoiseStableDetections  int        `json:"noiseStableDetections,omitempty"`
+	NoiseRecheckInterval   int        `json:"noiseRecheckInterval,omitempty"`
+	NoiseMode              string     `json:"noiseMode,omitempty"`
+
+	// Compiled ignore text patterns, set when Diferencia starts
+	ignoreTextPatterns []*regexp.Regexp
+	// Loaded JSON Schemas per endpoint followed by the global one, set when Diferencia starts
+	jsonSchemaEndpoints []jsonSchemaEndpoint
 }
 
 // UpdateConfiguration with configured params
@@ -155,7 +189,13 @@ func (conf *DiferenciaConfiguration) UpdateConfiguration(updateConfig Diferencia
 
 	}
 
-	return nil
+	// Learned noise belongs to the previous services and ignore configuration
+	if updateConfig.isServiceNameSet() || updateConfig.isPrimarySet() || updateConfig.isSecondarySet() || updateConfig.isCandidateSet() ||
+		updateConfig.isModeSet() || updateConfig.isNoiseDetectionSet() {
+		ResetLearnedNoise()
+	}
+
+	return conf.Compile()
 }
 
 func (conf DiferenciaConfiguration) GetServiceName() string {
@@ -177,16 +217,333 @@ func (conf DiferenciaConfiguration) IsStoreResultsSet() bool {
 	return len(conf.StoreResults) > 0
 }
 
+// Secondaries returns the URLs of secondary services, which are set separated by commas
+func (conf DiferenciaConfiguration) Secondaries() []string {
+	var secondaries []string
+	for _, secondary := range strings.Split(conf.Secondary, ",") {
+		if secondary = strings.TrimSpace(secondary); len(secondary) > 0 {
+			secondaries = append(secondaries, secondary)
+		}
+	}
+	return secondaries
+}
+
+// GetSecondaryQuorum returns the minimum number of secondaries that must be available to detect noise.
+// If quorum is not set, the majority of secondaries is required.
+func (conf DiferenciaConfiguration) GetSecondaryQuorum() int {
+	secondaries := len(conf.Secondaries())
+	quorum := conf.SecondaryQuorum
+
+	if quorum <= 0 {
+		quorum = secondaries/2 + 1
+	}
+
+	// Secondaries might have been updated without updating the quorum
+	if quorum > secondaries {
+		return secondaries
+	}
+
+	return quorum
+}
+
+// ValidateSecondaryQuorum checks that quorum can be reached with the configured secondaries
+func (conf DiferenciaConfiguration) ValidateSecondaryQuorum() error {
+	if conf.SecondaryQuorum < 0 || conf.SecondaryQuorum > len(conf.Secondaries()) {
+		return fmt.Errorf("Secondary quorum %d must be between 1 and the number of secondaries (%d)", conf.SecondaryQuorum, len(conf.Secondaries()))
+	}
+	return nil
+}
+
 // IsIgnoreValuesSet in configuration object
 func (conf DiferenciaConfiguration) IsIgnoreValuesSet() bool {
 	return conf.IgnoreValues != nil && len(conf.IgnoreValues) > 0
 }
 
+const (
+	// StrictNoise mode fails detecting noise if primary and secondary differ in something apart from values
+	StrictNoise = "Strict"
+	// TolerantNoise mode considers elements added or removed between primary and secondary as noise
+	TolerantNoise = "Tolerant"
+)
+
+// ValidateNoiseMode checks that noise mode is one of the supported ones
+func (conf DiferenciaConfiguration) ValidateNoiseMode() error {
+	switch conf.NoiseMode {
+	case "", StrictNoise, TolerantNoise:
+		return nil
+	}
+	return fmt.Errorf("Cannot find %s noise mode", conf.NoiseMode)
+}
+
+// IsTolerantNoiseEnabled in configuration object. If no mode is set, strict mode is used
+func (conf DiferenciaConfiguration) IsTolerantNoiseEnabled() bool {
+	return conf.NoiseMode == TolerantNoise
+}
+
+// ValidateNoiseLearning checks that learned noise can become stable and be checked again
+func (conf DiferenciaConfiguration) ValidateNoiseLearning() error {
+	if conf.NoiseStableDetections < 1 {
+		return fmt.Errorf("Noise stable detections %d must be greater than 0", conf.NoiseStableDetections)
+	}
+	if conf.NoiseRecheckInterval < 1 {
+		return fmt.Errorf("Noise recheck interval %d must be greater than 0", conf.NoiseRecheckInterval)
+	}
+	return nil
+}
+
+// DefaultMaxDecodedBodySize is the maximum size in megabytes of decoded bodies when it is not configured
+const DefaultMaxDecodedBodySize = 100
+
+// GetMaxDecodedBodySize returns the maximum size in bytes of bodies once they are decompressed
+func (conf DiferenciaConfiguration) GetMaxDecodedBodySize() int64 {
+	maxSize := conf.MaxDecodedBodySize
+	if maxSize < 1 {
+		maxSize = DefaultMaxDecodedBodySize
+	}
+	return int64(maxSize) << 20
+}
+
 // IsIgnoreValuesFileSet in configuration object
 func (conf DiferenciaConfiguration) IsIgnoreValuesFileSet() bool {
 	return len(conf.IgnoreValuesFile) > 0
 }
 
+// IsJsonSchemaSet in configuration object either globally or per endpoint
+func (conf DiferenciaConfiguration) IsJsonSchemaSet() bool {
+	return len(conf.JsonSchema) > 0 || len(conf.JsonSchemas) > 0
+}
+
+// ArrayKeysByPointer returns the identity field of arrays defined as pointer=field indexed by pointer
+func (conf DiferenciaConfiguration) ArrayKeysByPointer() (map[string]string, error) {
+	arrayKeys := make(map[string]string)
+
+	for _, definition := range conf.ArrayKeys {
+		separator := strings.LastIndex(definition, "=")
+
+		if separator < 0 || separator == len(definition)-1 {
+			return nil, fmt.Errorf("Array key definition %s does not follow pointer=field format", definition)
+		}
+
+		arrayKeys[definition[:separator]] = definition[separator+1:]
+	}
+
+	return arrayKeys, nil
+}
+
+// TolerancesByPointer returns the numeric tolerances defined as pointer=tolerance indexed by pointer
+func (conf DiferenciaConfiguration) TolerancesByPointer() (map[string]json.Tolerance, error) {
+	tolerances := make(map[string]json.Tolerance)
+
+	for _, definition := range conf.NumericTolerances {
+		separator := strings.LastIndex(definition, "=")
+
+		if separator < 0 {
+			return nil, fmt.Errorf("Numeric tolerance definition %s does not follow pointer=tolerance format", definition)
+		}
+
+		tolerance, err := json.ParseTolerance(definition[separator+1:])
+		if err != nil {
+			return nil, err
+		}
+
+		tolerances[definition[:separator]] = tolerance
+	}
+
+	return tolerances, nil
+}
+
+// ValueMatchersByPointer returns the value matchers defined as pointer=matcher indexed by pointer
+func (conf DiferenciaConfiguration) ValueMatchersByPointer() (map[string]json.ValueMatcher, error) {
+	matchers := make(map[string]json.ValueMatcher)
+
+	for _, definition := range conf.ValueMatchers {
+		// Regular expression matchers might contain =, so the first one is the separator, skipping the ones of JSONPath filters
+		separator := ruleSeparator(definition)
+
+		if separator < 0 || separator == len(definition)-1 {
+			return nil, fmt.Errorf("Value matcher definition %s does not follow pointer=matcher format", definition)
+		}
+
+		if err := json.ValidatePath(definition[:separator]); err != nil {
+			return nil, err
+		}
+
+		matcher, err := json.ParseValueMatcher(definition[separator+1:])
+		if err != nil {
+			return nil, err
+		}
+
+		matchers[definition[:separator]] = matcher
+	}
+
+	return matchers, nil
+}
+
+// ruleSeparator returns the index of the first = of a pointer=value definition that is not inside the brackets or quotes of a JSONPath expression
+func ruleSeparator(definition string) int {
+	depth := 0
+	var quote rune
+
+	for i, c := range definition {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == '=' && depth == 0:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ValidateIgnoreValues checks that JSONPath expressions of ignored values are valid
+func (conf DiferenciaConfiguration) ValidateIgnoreValues() error {
+	for _, path := range conf.IgnoreValues {
+		if err := json.ValidatePath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateIgnoreXPaths checks that XPath expressions of ignored XML nodes are valid
+func (conf DiferenciaConfiguration) ValidateIgnoreXPaths() error {
+	for _, expression := range conf.IgnoreXPaths {
+		if err := xml.ValidateXPath(expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateIgnoreSelectors checks that CSS selectors of ignored HTML elements are valid
+func (conf DiferenciaConfiguration) ValidateIgnoreSelectors() error {
+	for _, expression := range conf.IgnoreSelectors {
+		if err := html.ValidateSelector(expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDefaultTolerance returns the numeric tolerance applied to all numbers without a specific tolerance
+func (conf DiferenciaConfiguration) GetDefaultTolerance() (json.Tolerance, error) {
+	if len(conf.DefaultTolerance) == 0 {
+		return json.Tolerance{}, nil
+	}
+	return json.ParseTolerance(conf.DefaultTolerance)
+}
+
+// GetTextSimilarity returns the algorithm and the minimum percentage to consider texts equal defined as algorithm=percentage.
+// If text similarity is not set, Levenshtein algorithm is used with levenshtein percentage.
+func (conf DiferenciaConfiguration) GetTextSimilarity() (string, int, error) {
+	if len(conf.TextSimilarity) == 0 {
+		return plain.LevenshteinSimilarity, conf.LevenshteinPercentage, nil
+	}
+
+	separator := strings.LastIndex(conf.TextSimilarity, "=")
+
+	if separator < 0 {
+		return "", 0, fmt.Errorf("Text similarity %s does not follow algorithm=percentage format", conf.TextSimilarity)
+	}
+
+	algorithm := conf.TextSimilarity[:separator]
+	if _, err := plain.NewSimilarity(algorithm); err != nil {
+		return "", 0, err
+	}
+
+	percentage, err := strconv.Atoi(conf.TextSimilarity[separator+1:])
+	if err != nil || percentage < 0 || percentage > 100 {
+		return "", 0, fmt.Errorf("Text similarity percentage %s is not a number between 0 and 100", conf.TextSimilarity[separator+1:])
+	}
+
+	return algorithm, percentage, nil
+}
+
+// GetIgnoreTextPatterns returns the regular expressions of texts to ignore, both the ones set directly and the ones defined in each line of the file
+func (conf DiferenciaConfiguration) GetIgnoreTextPatterns() ([]*regexp.Regexp, error) {
+	expressions := append([]string(nil), conf.IgnoreTextPatterns...)
+
+	if len(conf.IgnoreTextPatternsFile) > 0 {
+		lines, err := readLines(conf.IgnoreTextPatternsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range lines {
+			if len(strings.TrimSpace(line)) > 0 {
+				expressions = append(expressions, line)
+			}
+		}
+	}
+
+	var patterns []*regexp.Regexp
+	for _, expression := range expressions {
+		pattern, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("Ignore text pattern %s is not a valid regular expression. %s", expression, err.Error())
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// CompileIgnoreTextPatterns reads and compiles the regular expressions of texts to ignore, so they are not compiled on each comparison
+func (conf *DiferenciaConfiguration) CompileIgnoreTextPatterns() error {
+	patterns, err := conf.GetIgnoreTextPatterns()
+	if err != nil {
+		return err
+	}
+	conf.ignoreTextPatterns = patterns
+	return nil
+}
+
+// Compile prepares the parts of the configuration that are used on each comparison, compiling ignore text patterns and loading JSON Schemas,
+// so they are only prepared once when Diferencia starts or its configuration is updated
+func (conf *DiferenciaConfiguration) Compile() error {
+	if err := conf.CompileIgnoreTextPatterns(); err != nil {
+		return err
+	}
+	return conf.CompileJsonSchemas()
+}
+
+const (
+	// TextBodyDiff format reports body differences as text
+	TextBodyDiff = "Text"
+	// PatchBodyDiff format reports body differences as JSON Patch operations
+	PatchBodyDiff = "Patch"
+	// AllBodyDiff format reports body differences both as text and as JSON Patch operations
+	AllBodyDiff = "All"
+)
+
+// ValidateBodyDiffFormat checks that body diff format is one of the supported ones
+func (conf DiferenciaConfiguration) ValidateBodyDiffFormat() error {
+	switch conf.BodyDiffFormat {
+	case "", TextBodyDiff, PatchBodyDiff, AllBodyDiff:
+		return nil
+	}
+	return fmt.Errorf("Cannot find %s body diff format", conf.BodyDiffFormat)
+}
+
+// IsTextBodyDiffEnabled in configuration object. If no format is set, all formats are enabled
+func (conf DiferenciaConfiguration) IsTextBodyDiffEnabled() bool {
+	return conf.BodyDiffFormat != PatchBodyDiff
+}
+
+// IsPatchBodyDiffEnabled in configuration object. If no format is set, all formats are enabled
+func (conf DiferenciaConfiguration) IsPatchBodyDiffEnabled() bool {
+	return conf.BodyDiffFormat != TextBodyDiff
+}
+
 // AreHttpsClientParamsSet checking if https is enabled
 func (conf DiferenciaConfiguration) AreHttpsClientParamsSet() bool {
 	return (len(conf.CaCert) > 0 && len(conf.ClientCert) > 0 && len(conf.ClientKey) > 0)
@@ -200,12 +557,19 @@ func (conf DiferenciaConfiguration) Print() {
 	fmt.Printf("Service Name: %s\n", conf.ServiceName)
 	fmt.Printf("Primary: %s\n", conf.Primary)
 	fmt.Printf("Secondary: %s\n", conf.Secondary)
+	fmt.Printf("Secondary Quorum: %d\n", conf.GetSecondaryQuorum())
 	fmt.Printf("Candidate: %s\n", conf.Candidate)
 	fmt.Printf("Difference Mode: %s\n", conf.DifferenceMode.String())
 	fmt.Printf("Noise Detection: %t\n", conf.NoiseDetection)
+	fmt.Printf("Noise Mode: %s\n", conf.NoiseMode)
+	fmt.Printf("Learn Noise: %t\n", conf.LearnNoise)
+	fmt.Printf("Noise Stable Detections: %d\n", conf.NoiseStableDetections)
+	fmt.Printf("Noise Recheck Interval: %d\n", conf.NoiseRecheckInterval)
 	fmt.Printf("Store Results: %s\n", conf.StoreResults)
 	fmt.Printf("Ignore Values of: %v\n", conf.IgnoreValues)
 	fmt.Printf("Ignore Values File: %s\n", conf.IgnoreValuesFile)
+	fmt.Printf("Ignore Text Patterns: %v\n", conf.IgnoreTextPatterns)
+	fmt.Printf("Ignore Text Patterns File: %s\n", conf.IgnoreTextPatternsFile)
 	fmt.Printf("Headers: %t\n", conf.Headers)
 	fmt.Printf("Ignored Headers Values of: %v\n", conf.IgnoreHeadersValues)
 	fmt.Printf("Allow Unsafe Operations: %t\n", conf.AllowUnsafeOperations)
@@ -218,6 +582,20 @@ func (conf DiferenciaConfiguration) Print() {
 	fmt.Printf("Force Plain Text: %t\n", conf.ForcePlainText)
 	fmt.Printf("Mirroring: %t\n", conf.Mirroring)
 	fmt.Printf("Return Result: %t\n", conf.ReturnResult)
+	fmt.Printf("Json Schema: %s\n", conf.JsonSchema)
+	fmt.Printf("Json Schemas per endpoint: %v\n", conf.JsonSchemas)
+	fmt.Printf("Unordered Arrays: %v\n", conf.UnorderedArrays)
+	fmt.Printf("Array Keys: %v\n", conf.ArrayKeys)
+	fmt.Printf("Numeric Tolerances: %v\n", conf.NumericTolerances)
+	fmt.Printf("Default Tolerance: %s\n", conf.DefaultTolerance)
+	fmt.Printf("Value Matchers: %v\n", conf.ValueMatchers)
+	fmt.Printf("Body Diff Format: %s\n", conf.BodyDiffFormat)
+	fmt.Printf("Ignore XPaths: %v\n", conf.IgnoreXPaths)
+	fmt.Printf("Ignore Selectors: %v\n", conf.IgnoreSelectors)
+	fmt.Printf("Comparators: %v\n", conf.Comparators)
+	fmt.Printf("Compare Content Encoding: %t\n", conf.CompareContentEncoding)
+	fmt.Printf("Max Decoded Body Size: %d MB\n", conf.MaxDecodedBodySize)
+	fmt.Printf("Text Similarity: %s\n", conf.TextSimilarity)
 }
 
 type DiferenciaError struct {
@@ -248,11 +626,33 @@ type Result struct {
 	Diff                 DifferenceDescription
 }
 
+// PatchOperation is a JSON Patch (RFC 6902) operation that transforms primary body into candidate body.
+// Primary member is not part of the RFC and contains the primary value of replaced and removed elements.
+type PatchOperation = json.Operation
+
 // DifferenceDescription offers the description of the differences
 type DifferenceDescription struct {
-	HeadersDiff string `json:"headersDiff,omitempty"`
-	BodyDiff    string `json:"bodyDiff,omitempty"`
-	StatusDiff  string `json:"statusDiff,omitempty"`
+	HeadersDiff string           `json:"headersDiff,omitempty"`
+	BodyDiff    string           `json:"bodyDiff,omitempty"`
+	BodyPatch   []PatchOperation `json:"bodyPatch,omitempty"`
+	StatusDiff  string           `json:"statusDiff,omitempty"`
+	SchemaDiff  string           `json:"schemaDiff,omitempty"`
+	CharsetDiff string           `json:"charsetDiff,omitempty"`
+	MatcherDiff string           `json:"matcherDiff,omitempty"`
+}
+
+// marshalBodyPatch returns the body patch as JSON so it can be exported, or nil if there is no patch
+func (d DifferenceDescription) marshalBodyPatch() jsonenc.RawMessage {
+	if len(d.BodyPatch) == 0 {
+		return nil
+	}
+
+	content, err := jsonenc.Marshal(d.BodyPatch)
+	if err != nil {
+		logrus.WithError(err).Warnf("Body patch cannot be exported.")
+		return nil
+	}
+	return content
 }
 
 // MarshallJson translate object to byte[]
@@ -306,64 +706,80 @@ func Diferencia(r *http.Request) (Result, Communicationcontent, error) {
 		return Result{EqualContent: false}, Communicationcontent{Content: primaryBodyContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, &DiferenciaError{http.StatusServiceUnavailable, fmt.Sprintf("Error while connecting to Candidate site (%s) with %s", candidateFullURL, err.Error())}
 	}
 
+	// Mirroring returns primary content as it was received
+	primaryRawContent := primaryBodyContent
+	primaryBodyContent, err = decodeResponse(primaryBodyContent, primaryHeader, primaryFullURL)
+	if err == nil {
+		candidateBodyContent, err = decodeResponse(candidateBodyContent, candidateHeader, candidateFullURL)
+	}
+	if err != nil {
+		logrus.Errorf("%s", err.Error())
+		return Result{EqualContent: false}, Communicationcontent{Content: primaryRawContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, &DiferenciaError{http.StatusBadGateway, err.Error()}
+	}
+
 	var result bool
 
-	var secondaryFullURL string
-	var secondaryBodyContent []byte
-	var secondaryStatus int
-	if Config.NoiseDetection {
-		// Get secondary to do the noise cancellation
-		secondaryFullURL := CreateUrl(*r.URL, Config.Secondary)
-		logrus.Debugf("Forwarding call to %s", secondaryFullURL)
-		secondaryBodyContent, secondaryStatus, _, _, err := getContent(r, secondaryFullURL)
-		if err != nil {
-			logrus.Errorf("Error while connecting to Secondary site (%s) with error %s", candidateFullURL, err.Error())
-			return Result{EqualContent: false}, Communicationcontent{Content: primaryBodyContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, &DiferenciaError{http.StatusServiceUnavailable, fmt.Sprintf("Error while connecting to Secondary site (%s) with error %s", candidateFullURL, err.Error())}
-		}
-		// If status code is equal then we detect noise and and remove from primary and candidate
-		// What to do in case of two identical status code but no body content (404) might be still valid since you are testing that nothing is there
-		if primaryStatus == secondaryStatus {
-
-			contentType := primaryHeader.Get("Content-Type")
-			var err error
-			switch {
-			case strings.HasPrefix(contentType, "application/json"):
-				primaryBodyContent, candidateBodyContent, err = noiseCancellationJson(primaryBodyContent, secondaryBodyContent, candidateBodyContent)
-			case strings.HasPrefix(contentType, "text/plain"):
-				primaryBodyContent, candidateBodyContent = noiseCancellationText(primaryBodyContent, secondaryBodyContent, candidateBodyContent)
-			default:
-				{
-					if Config.ForcePlainText {
-						primaryBodyContent, candidateBodyContent = noiseCancellationText(primaryBodyContent, secondaryBodyContent, candidateBodyContent)
-					} else {
-						primaryBodyContent, candidateBodyContent, err = noiseCancellationJson(primaryBodyContent, secondaryBodyContent, candidateBodyContent)
-					}
-				}
-			}
+	contentType := primaryHeader.Get("Content-Type")
+	comparator, ok := findComparator(contentType)
+	if !ok {
+		comparator = defaultComparator()
+	}
 
-			if err != nil {
-				logrus.WithError(err).Errorf("Error detecting noise between %s and %s.", primaryFullURL, secondaryFullURL)
-				return Result{EqualContent: false}, Communicationcontent{Content: primaryBodyContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, &DiferenciaError{http.StatusBadRequest, fmt.Sprintf("Error detecting noise between %s and %s. (%s)", primaryFullURL, secondaryFullURL, err.Error())}
-			}
+	// Candidate content is validated before noise cancellation modifies it, and only if its comparator validates JSON Schemas
+	schemaEqual, schemaDiff := true, ""
+	if comparator.validatesSchema() {
+		schemaEqual, schemaDiff = validateJsonSchema(r, candidateBodyContent)
+	}
+	candidateBodyContent, matcherDiff := matchValues(comparator, primaryBodyContent, candidateBodyContent)
+
+	var secondaries []secondaryResponse
+	if Config.NoiseDetection {
+		primaryBody := Body{Content: primaryBodyContent, ContentType: contentType}
+		candidateBody := Body{Content: candidateBodyContent, ContentType: responseContentType(candidateHeader, contentType)}
 
+		var diferenciaError *DiferenciaError
+		if learner, ok := comparator.noiseDetector.(NoiseLearner); ok && Config.LearnNoise {
+			primaryBodyContent, candidateBodyContent, secondaries, diferenciaError = learnNoise(r, learner, primaryFullURL, primaryStatus, primaryBody, candidateBody)
 		} else {
-			logrus.Errorf("Status code between %s(%d) and %s(%d) are different", primaryFullURL, primaryStatus, secondaryFullURL, secondaryStatus)
-			return Result{EqualContent: false}, Communicationcontent{Content: primaryBodyContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, &DiferenciaError{http.StatusBadRequest, fmt.Sprintf("Status code between %s(%d) and %s(%d) are different", primaryFullURL, primaryStatus, secondaryFullURL, secondaryStatus)}
+			primaryBodyContent, candidateBodyContent, secondaries, diferenciaError = detectNoise(r, comparator.noiseDetector, primaryFullURL, primaryStatus, primaryBody, candidateBody)
+		}
+
+		if diferenciaError != nil {
+			return Result{EqualContent: false}, Communicationcontent{Content: primaryRawContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, diferenciaError
 		}
 	}
 
 	result, output := compareResult(candidateBodyContent, primaryBodyContent, candidateStatus, primaryStatus, candidateHeader, primaryHeader)
 
+	if !schemaEqual {
+		result = false
+		output.SchemaDiff = schemaDiff
+	}
+
+	if len(matcherDiff) > 0 {
+		result = false
+		output.MatcherDiff = matcherDiff
+	}
+
 	if Config.IsStoreResultsSet() {
-		primary := exporter.CreateInteraction(primaryFullURL, primaryBodyContent, primaryStatus)
-		candidate := exporter.CreateInteraction(candidateFullURL, candidateBodyContent, candidateStatus)
+		// Binary bodies cannot be stored as text
+		createInteraction := exporter.CreateInteraction
+		if comparator.name == "Binary" {
+			createInteraction = exporter.CreateBinaryInteraction
+		}
+
+		primary := createInteraction(primaryFullURL, primaryBodyContent, primaryStatus)
+		candidate := createInteraction(candidateFullURL, candidateBodyContent, candidateStatus)
 		var secondary exporter.Interaction
 
-		if Config.NoiseDetection {
-			secondary = exporter.CreateInteraction(secondaryFullURL, secondaryBodyContent, secondaryStatus)
+		// Only the first secondary is stored
+		if len(secondaries) > 0 {
+			secondary = createInteraction(secondaries[0].url, secondaries[0].content, secondaries[0].status)
 		}
 
 		interactions := exporter.CreateInteractions(primary, &secondary, candidate, Config.DifferenceMode.String(), result)
+		interactions.BodyDiff = output.BodyDiff
+		interactions.BodyPatch = output.marshalBodyPatch()
 
 		exporter.ExportToFile(Config.StoreResults, interactions)
 	}
@@ -389,7 +805,7 @@ func Diferencia(r *http.Request) (Result, Communicationcontent, error) {
 		logrus.Debugf("************************")
 	}
 
-	return Result{EqualContent: result, PrimaryElapsedTime: primaryElapsedDuration, CandidateElapsedTime: candidateElapsedDuration, Diff: output}, Communicationcontent{Content: primaryBodyContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, nil
+	return Result{EqualContent: result, PrimaryElapsedTime: primaryElapsedDuration, CandidateElapsedTime: candidateElapsedDuration, Diff: output}, Communicationcontent{Content: primaryRawContent, StatusCode: primaryStatus, Header: primaryHeader, Cookies: cookies}, nil
 
 }
 
@@ -401,30 +817,6 @@ func createKeyValuePairs(m http.Header) string {
 	return b.String()
 }
 
-func noiseCancellationText(primaryBodyContent, secondaryBodyContent, candidateBodyContent []byte) ([]byte, []byte) {
-
-	noiseOperation := plain.NoiseOperation{}
-	noiseOperation.Detect(primaryBodyContent, secondaryBodyContent)
-
-	primaryWithoutNoise, candidateWithoutNoise := noiseOperation.Remove(primaryBodyContent, candidateBodyContent)
-
-	return primaryWithoutNoise, candidateWithoutNoise
-
-}
-
-func noiseCancellationJson(primaryBodyContent, secondaryBodyContent, candidateBodyContent []byte) ([]byte, []byte, error) {
-	noiseOperation := json.NoiseOperation{}
-	manualNoise := manualNoiseDetection()
-	noiseOperation.Initialize(manualNoise)
-	err := noiseOperation.Detect(primaryBodyContent, secondaryBodyContent)
-	if err != nil {
-		return nil, nil, err
-	}
-	primaryWithoutNoise, candidateWithoutNoise, _ := noiseOperation.Remove(primaryBodyContent, candidateBodyContent)
-
-	return primaryWithoutNoise, candidateWithoutNoise, nil
-}
-
 func manualNoiseDetection() []string {
 	var pointers []string
 
@@ -469,52 +861,60 @@ func readLines(path string) ([]string, error) {
 
 func compareResult(candidate, primary []byte, candidateStatus, primaryStatus int, candidateHeader, primaryHeader http.Header) (bool, DifferenceDescription) {
 
-	// TODO This method should be refactored to a chain of responsibility pattern
 	if primaryStatus == candidateStatus {
 		headersDiff := ""
 		headerEqual := true
 		if Config.Headers {
 			headerEqual, headersDiff = header.CompareHeaders(candidateHeader, primaryHeader, Config.IgnoreHeadersValues...)
+		} else if Config.CompareContentEncoding {
+			// Content-Encoding is already compared when all headers are compared
+			headerEqual, headersDiff = compareContentEncoding(candidateHeader, primaryHeader)
 		}
 		// Comparision between documents
 		contentType := primaryHeader.Get("Content-Type")
-		switch {
-		case strings.HasPrefix(contentType, "application/json"):
-			bodyEqual, bodyDiff := json.CompareDocuments(candidate, primary, Config.DifferenceMode.String())
+		comparator, ok := findComparator(contentType)
+		if !ok {
+			comparator = defaultComparator()
+		}
 
-			if headerEqual && bodyEqual {
-				return bodyEqual, DifferenceDescription{}
-			}
+		bodyEqual, description := comparator.comparator.Compare(Body{Content: candidate, ContentType: responseContentType(candidateHeader, contentType)}, Body{Content: primary, ContentType: contentType})
 
-			return bodyEqual && headerEqual, DifferenceDescription{HeadersDiff: headersDiff, BodyDiff: bodyDiff}
-		case strings.HasPrefix(contentType, "text/plain"):
-			return compareText(candidate, primary, Config.LevenshteinPercentage), DifferenceDescription{}
-		default:
-			{
-				if Config.ForcePlainText {
-					return compareText(candidate, primary, Config.LevenshteinPercentage), DifferenceDescription{}
-				}
-				bodyEqual, bodyDiff := json.CompareDocuments(candidate, primary, Config.DifferenceMode.String())
-
-				if headerEqual && bodyEqual {
-					return bodyEqual, DifferenceDescription{}
-				}
-
-				return bodyEqual && headerEqual, DifferenceDescription{HeadersDiff: headersDiff, BodyDiff: bodyDiff}
-			}
-		}
+		description.HeadersDiff = headersDiff
+		return headerEqual && bodyEqual, description
 	}
 
 	return false, DifferenceDescription{StatusDiff: fmt.Sprintf(`"status": %d => %d`, primaryStatus, candidateStatus)}
 }
 
-func compareText(candidate, primary []byte, levenshtein int) bool {
-	if levenshtein < 100 {
-		dif := int(plain.CalculateSimilarity(primary, candidate) * 100)
-		return dif > levenshtein
+// responseContentType returns the content type of the response, since for example each multipart form has its own boundary, or the primary one if not set
+func responseContentType(responseHeader http.Header, primaryContentType string) string {
+	if contentType := responseHeader.Get("Content-Type"); len(contentType) > 0 {
+		return contentType
+	}
+	return primaryContentType
+}
+
+func jsonOptions() json.Options {
+	// Array keys and tolerances format is validated when Diferencia starts.
+	// Value matchers are not set since they are applied to the original candidate before noise cancellation.
+	arrayKeys, _ := Config.ArrayKeysByPointer()
+	tolerances, _ := Config.TolerancesByPointer()
+	defaultTolerance, _ := Config.GetDefaultTolerance()
+
+	options := json.Options{
+		UnorderedArrays:  Config.UnorderedArrays,
+		ArrayKeys:        arrayKeys,
+		Tolerances:       tolerances,
+		DefaultTolerance: defaultTolerance,
+	}
+
+	if patterns := Config.ignoreTextPatterns; len(patterns) > 0 {
+		options.Mask = func(value string) string {
+			return string(plain.Mask([]byte(value), patterns))
+		}
 	}
 
-	return bytes.Equal(candidate, primary)
+	return options
 }
 
 func healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -570,7 +970,18 @@ func diferenciaHandler(w http.ResponseWriter, r *http.Request) {
 		if Config.Prometheus {
 			prometheusCounter.WithLabelValues(r.Method, r.URL.Path).Inc()
 		}
-		exporter.IncrementError(r.Method, r.URL.Path, string(body[:]), r.URL.RequestURI(), result.Diff.HeadersDiff, result.Diff.BodyDiff, result.Diff.StatusDiff, r.Header)
+		exporter.IncrementErrorData(r.Method, r.URL.Path, exporter.ErrorData{
+			FullURI:         r.URL.RequestURI(),
+			OriginalBody:    string(body[:]),
+			OriginalHeaders: r.Header,
+			HeaderDiff:      result.Diff.HeadersDiff,
+			BodyDiff:        result.Diff.BodyDiff,
+			BodyPatch:       result.Diff.marshalBodyPatch(),
+			StatusDiff:      result.Diff.StatusDiff,
+			SchemaDiff:      result.Diff.SchemaDiff,
+			CharsetDiff:     result.Diff.CharsetDiff,
+			MatcherDiff:     result.Diff.MatcherDiff,
+		})
 	}
 }
 
@@ -595,13 +1006,15 @@ func getContent(r *http.Request, url string) ([]byte, int, http.Header, []*http.
 
 }
 
-// StartProxy server
-func StartProxy(configuration *DiferenciaConfiguration) {
+// StartProxy starts proxy, prometheus and admin servers, or returns an error if configuration cannot be compiled
+func StartProxy(configuration *DiferenciaConfiguration) error {
 
 	finish := make(chan bool)
 
 	Config = configuration
-	initialize()
+	if err := initialize(); err != nil {
+		return err
+	}
 
 	go func() {
 		// Initialize Proxy server
@@ -626,22 +1039,30 @@ func StartProxy(configuration *DiferenciaConfiguration) {
 		adminMux := http.NewServeMux()
 		adminMux.HandleFunc("/configuration", adminHandler)
 		adminMux.HandleFunc("/stats", exporter.StatsHandler)
+		adminMux.HandleFunc("/noise", learnedNoiseHandler)
+		adminMux.HandleFunc("/noise/export", learnedNoiseExportHandler)
 		adminMux.HandleFunc("/dashboard/details", dashboardDetailsHandler)
 		adminMux.HandleFunc("/dashboard/", dashboardHandler)
 		logrus.Errorf("Error starting admin: %s", http.ListenAndServe(":"+strconv.Itoa(Config.AdminPort), adminMux))
 	}()
 
 	<-finish
+	return nil
 }
 
-func initialize() {
+func initialize() error {
 
 	// Print config object
 	Config.Print()
 
+	if err := Config.Compile(); err != nil {
+		return err
+	}
+
 	//Initialize Prometheus if required
 	if Config.Prometheus {
 		prometheusCounter = metrics.RegisterNumberOfRegressions(Config.ServiceName)
 	}
 
+	return nil
 }
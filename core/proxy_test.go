@@ -1,13 +1,16 @@
 package core_test
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/lordofthejars/diferencia/core"
+	"github.com/lordofthejars/diferencia/difference/json"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -16,10 +19,15 @@ type StubHttpClient struct {
 	header  []http.Header
 	content []string
 	status  []int
+	errors  []error
 	index   int
 }
 
 func (httpClient *StubHttpClient) MakeRequest(r *http.Request, url string) (*http.Response, error) {
+	if httpClient.errors != nil && httpClient.errors[httpClient.index] != nil {
+		httpClient.index += 1
+		return nil, httpClient.errors[httpClient.index-1]
+	}
 	response := &http.Response{}
 	buff := ioutil.NopCloser(strings.NewReader(httpClient.content[httpClient.index]))
 	response.Body = buff
@@ -158,6 +166,36 @@ var _ = Describe("Proxy", func() {
 
 				Expect(err).Should(HaveOccurred())
 			})
+
+			It("should fail if ignore text patterns cannot be compiled", func() {
+
+				// Given
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					IgnoreTextPatterns:    []string{"[0-9"},
+				}
+				core.Config = conf
+
+				updateConf := core.DiferenciaConfigurationUpdate{
+					NoiseDetection: "true",
+				}
+
+				// When
+
+				err := core.Config.UpdateConfiguration(updateConf)
+
+				// Then
+
+				Expect(err).Should(HaveOccurred())
+			})
 		})
 	})
 
@@ -340,6 +378,155 @@ var _ = Describe("Proxy", func() {
 
 				Expect(result.EqualContent).Should(Equal(false))
 				Expect(len(result.Diff.BodyDiff)).Should(BeNumerically(">", 0))
+				Expect(result.Diff.BodyPatch).Should(HaveLen(4))
+				Expect(result.Diff.BodyPatch[0].Op).Should(Equal("replace"))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return true if changed values match their value matchers", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					ValueMatchers:         []string{"/now/epoch=number", "/now/iso8601=iso8601", "/now/rfc3339=iso8601", "/now/rfc2822=regex:GMT$"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should return only differences of texts not matching ignored text patterns", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					IgnoreTextPatterns:    []string{`\d{2}:\d{2}:\d{2}(\.\d+)?`},
+				}
+				core.Config = conf
+				Expect(conf.CompileIgnoreTextPatterns()).Should(Succeed())
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyPatch).Should(HaveLen(1))
+				Expect(result.Diff.BodyPatch[0].Path).Should(Equal("/now/epoch"))
+				Expect(err).Should(Succeed())
+			})
+			It("should return only JSON Patch operations if patch body diff format is set", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					BodyDiffFormat:        core.PatchBodyDiff,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyDiff).Should(Equal(""))
+				Expect(result.Diff.BodyPatch).Should(ContainElement(json.Operation{Op: "replace", Path: "/now/rfc3339", Value: []byte(`"2018-06-18T13:45:05.83Z"`), Primary: []byte(`"2018-06-18T11:46:23.87Z"`)}))
+				Expect(err).Should(Succeed())
+			})
+			It("should return value matcher mismatches if patch body diff format is set", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					BodyDiffFormat:        core.PatchBodyDiff,
+					ValueMatchers:         []string{"/now/epoch=uuid"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyPatch).Should(BeEmpty())
+				Expect(result.Diff.MatcherDiff).Should(ContainSubstring(`"/now/epoch"`))
 				Expect(err).Should(Succeed())
 			})
 		})
@@ -381,12 +568,12 @@ var _ = Describe("Proxy", func() {
 				Expect(err).Should(Succeed())
 			})
 
-			It("should return true if both documents are same but with different values not detected by automatic noise reduction but by manual", func() {
+			It("should return true if secondary removes elements and noise mode is tolerant", func() {
 
 				// Given
 				var httpClient = &StubHttpClient{}
 				// Record Http Client responses
-				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date-and-slang-time.json", "test_fixtures/document-a-change-date.json")
+				recordContent(httpClient, "test_fixtures/profile.json", "test_fixtures/profile-candidate.json", "test_fixtures/profile-secondary.json")
 				recordStatus(httpClient, 200, 200, 200)
 				core.HttpClient = httpClient
 
@@ -399,8 +586,8 @@ var _ = Describe("Proxy", func() {
 					StoreResults:          "",
 					DifferenceMode:        core.Strict,
 					NoiseDetection:        true,
+					NoiseMode:             core.TolerantNoise,
 					AllowUnsafeOperations: false,
-					IgnoreValues:          []string{"/now/slang_time"},
 				}
 				core.Config = conf
 
@@ -409,6 +596,7 @@ var _ = Describe("Proxy", func() {
 				request := createRequest(http.MethodGet, url)
 
 				// When
+
 				result, _, err := core.Diferencia(&request)
 
 				//Then
@@ -416,12 +604,13 @@ var _ = Describe("Proxy", func() {
 				Expect(result.EqualContent).Should(Equal(true))
 				Expect(err).Should(Succeed())
 			})
-			It("should return true if both documents are same but with different values not detected by automatic noise reduction but by manual file", func() {
+
+			It("should return false if candidate lacks elements removed by secondary and noise mode is tolerant", func() {
 
 				// Given
 				var httpClient = &StubHttpClient{}
 				// Record Http Client responses
-				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date-and-slang-time.json", "test_fixtures/document-a-change-date.json")
+				recordContent(httpClient, "test_fixtures/profile.json", "test_fixtures/profile-secondary.json", "test_fixtures/profile-secondary.json")
 				recordStatus(httpClient, 200, 200, 200)
 				core.HttpClient = httpClient
 
@@ -434,8 +623,8 @@ var _ = Describe("Proxy", func() {
 					StoreResults:          "",
 					DifferenceMode:        core.Strict,
 					NoiseDetection:        true,
+					NoiseMode:             core.TolerantNoise,
 					AllowUnsafeOperations: false,
-					IgnoreValuesFile:      "test_fixtures/manual_noise.txt",
 				}
 				core.Config = conf
 
@@ -444,50 +633,1219 @@ var _ = Describe("Proxy", func() {
 				request := createRequest(http.MethodGet, url)
 
 				// When
+
 				result, _, err := core.Diferencia(&request)
 
 				//Then
 
-				Expect(result.EqualContent).Should(Equal(true))
+				Expect(result.EqualContent).Should(Equal(false))
 				Expect(err).Should(Succeed())
 			})
-		})
 
-		Context("With incorrect configuration", func() {
-			It("should return error if safe enabled and unsafe operation", func() {
+			It("should return error if secondary removes elements and noise mode is strict", func() {
 
 				// Given
 				var httpClient = &StubHttpClient{}
 				// Record Http Client responses
-				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
-				recordStatus(httpClient, 200, 200)
+				recordContent(httpClient, "test_fixtures/profile.json", "test_fixtures/profile-candidate.json", "test_fixtures/profile-secondary.json")
+				recordStatus(httpClient, 200, 200, 200)
 				core.HttpClient = httpClient
 
 				// Prepare Configuration object
 				conf := &core.DiferenciaConfiguration{
 					Port:                  8080,
 					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
 					Candidate:             "http://now.httpbin.org/",
 					StoreResults:          "",
 					DifferenceMode:        core.Strict,
-					NoiseDetection:        false,
+					NoiseDetection:        true,
+					NoiseMode:             core.StrictNoise,
 					AllowUnsafeOperations: false,
 				}
 				core.Config = conf
 
 				// Create stubbed http.Request object
 				url, _ := url.Parse("http://localhost:8080")
-				request := createRequest(http.MethodPost, url)
+				request := createRequest(http.MethodGet, url)
 
 				// When
 
-				result, _, err := core.Diferencia(&request)
+				_, _, err := core.Diferencia(&request)
 
 				//Then
 
-				Expect(result.EqualContent).Should(Equal(false))
 				Expect(err).Should(HaveOccurred())
 			})
+
+			It("should return true using learned noise without calling secondary once noise is stable", func() {
+
+				// Given
+				core.ResetLearnedNoise()
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses, last request is not forwarded to secondary
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json", "test_fixtures/document-a-change-date.json",
+					"test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json", "test_fixtures/document-a-change-date.json",
+					"test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200, 200, 200, 200, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					LearnNoise:            true,
+					NoiseStableDetections: 1,
+					NoiseRecheckInterval:  10,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080/now")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				for i := 0; i < 3; i++ {
+					result, _, err := core.Diferencia(&request)
+
+					Expect(result.EqualContent).Should(Equal(true))
+					Expect(err).Should(Succeed())
+				}
+
+				//Then
+
+				learned := core.LearnedNoises()
+				Expect(learned).Should(HaveLen(1))
+				Expect(learned[0].Detections).Should(Equal(2))
+				Expect(learned[0].RequestsSinceDetection).Should(Equal(1))
+				Expect(learned[0].Pointers).Should(HaveKeyWithValue("/now/epoch", 2))
+				Expect(httpClient.index).Should(Equal(8))
+				Expect(core.ExportLearnedNoise(http.MethodGet, "/now")).Should(Equal("/now/epoch\n/now/iso8601\n/now/rfc2822\n/now/rfc3339\n"))
+				Expect(core.ExportLearnedNoise(http.MethodPost, "/now")).Should(BeEmpty())
+			})
+
+			It("should return true using exported learned noise as ignore values file", func() {
+
+				// Given
+				core.ResetLearnedNoise()
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses, first request learns noise and second one uses exported noise without secondary
+				recordContent(httpClient, "test_fixtures/shipments.json", "test_fixtures/shipments-changed-eta.json", "test_fixtures/shipments-changed-eta.json",
+					"test_fixtures/shipments.json", "test_fixtures/shipments-changed-eta.json", "test_fixtures/shipments.json")
+				recordStatus(httpClient, 200, 200, 200, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					LearnNoise:            true,
+					NoiseStableDetections: 1,
+					NoiseRecheckInterval:  10,
+					ArrayKeys:             []string{"/shipments=id"},
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080/shipments")
+				request := createRequest(http.MethodGet, url)
+
+				result, _, err := core.Diferencia(&request)
+				Expect(err).Should(Succeed())
+				Expect(result.EqualContent).Should(Equal(true))
+
+				exported := core.ExportLearnedNoise(http.MethodGet, "/shipments")
+				Expect(exported).Should(Equal("$['shipments'][?(@.id == 2)]['eta']\n"))
+
+				ignoreValuesFile, err := ioutil.TempFile("", "learned_noise.txt")
+				Expect(err).Should(Succeed())
+				defer os.Remove(ignoreValuesFile.Name())
+				ignoreValuesFile.WriteString(exported)
+				ignoreValuesFile.Close()
+
+				core.ResetLearnedNoise()
+				conf.LearnNoise = false
+				conf.IgnoreValuesFile = ignoreValuesFile.Name()
+
+				// When
+
+				result, _, err = core.Diferencia(&request)
+
+				//Then
+
+				Expect(err).Should(Succeed())
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(httpClient.index).Should(Equal(6))
+			})
+
+			It("should reset learned noise when configuration is updated", func() {
+
+				// Given
+				core.ResetLearnedNoise()
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					LearnNoise:            true,
+					NoiseStableDetections: 1,
+					NoiseRecheckInterval:  10,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080/now")
+				request := createRequest(http.MethodGet, url)
+
+				_, _, err := core.Diferencia(&request)
+				Expect(err).Should(Succeed())
+				Expect(core.LearnedNoises()).Should(HaveLen(1))
+
+				// When
+
+				err = conf.UpdateConfiguration(core.DiferenciaConfigurationUpdate{Primary: "http://other.httpbin.org/"})
+
+				//Then
+
+				Expect(err).Should(Succeed())
+				Expect(core.LearnedNoises()).Should(BeEmpty())
+			})
+
+			It("should return true if both documents are same but with different values not detected by automatic noise reduction but by manual", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date-and-slang-time.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					IgnoreValues:          []string{"/now/slang_time"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should return true if both documents are same but with different values ignored by manual wildcard pointers", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date-and-slang-time.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					IgnoreValues:          []string{"/**/slang_time", "/urls/*/name"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should return true if changed values are ignored or matched using JSONPath expressions", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/orders.json", "test_fixtures/orders-changed.json", "test_fixtures/orders.json")
+				recordStatus(httpClient, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					IgnoreValues:          []string{"$.orders[?(@.status=='PENDING')].eta"},
+					ValueMatchers:         []string{"$.orders[?(@.status=='SHIPPED')].eta=iso8601"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should return true if changed values match their value matchers before noise is removed", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/account.json", "test_fixtures/account-candidate.json", "test_fixtures/account-secondary.json")
+				recordStatus(httpClient, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					ValueMatchers:         []string{"/id=uuid", "/createdAt=iso8601"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(result.Diff.MatcherDiff).Should(Equal(""))
+				Expect(err).Should(Succeed())
+			})
+			It("should return true if both documents are same but with different values not detected by automatic noise reduction but by manual file", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date-and-slang-time.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					IgnoreValuesFile:      "test_fixtures/manual_noise.txt",
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should return true if noise detected by any of the secondaries is the only difference", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/session.json", "test_fixtures/session-candidate.json", "test_fixtures/session-secondary-time.json", "test_fixtures/session-secondary-token.json")
+				recordStatus(httpClient, 200, 200, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/, http://later.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should detect noise with available secondaries if quorum is reached", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				httpClient.content = []string{loadFromFile("test_fixtures/session.json"), loadFromFile("test_fixtures/session-candidate.json"), "", loadFromFile("test_fixtures/session-secondary-time.json"), loadFromFile("test_fixtures/session-secondary-token.json")}
+				recordStatus(httpClient, 200, 200, 0, 200, 200)
+				httpClient.errors = []error{nil, nil, errors.New("connection refused"), nil, nil}
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://down.httpbin.org/,http://now.httpbin.org/,http://later.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+			It("should return an error if quorum of secondaries is not reached", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				httpClient.content = []string{loadFromFile("test_fixtures/session.json"), loadFromFile("test_fixtures/session-candidate.json"), "", loadFromFile("test_fixtures/session-secondary-time.json")}
+				recordStatus(httpClient, 200, 200, 0, 200)
+				httpClient.errors = []error{nil, nil, errors.New("connection refused"), nil}
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://down.httpbin.org/,http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					SecondaryQuorum:       2,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(ContainSubstring("connection refused"))
+			})
+		})
+
+		Context("With JSON Schema validation", func() {
+			It("should validate candidate against endpoint schema instead of comparing with primary in JsonSchema mode", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a-change-date.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.JsonSchema,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					JsonSchemas:           []string{"POST /*=test_fixtures/document-a-schema.json", "GET /*=test_fixtures/document-a-schema.json"},
+				}
+				Expect(conf.CompileJsonSchemas()).Should(Succeed())
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080/now")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyDiff).Should(Equal(""))
+				Expect(result.Diff.SchemaDiff).Should(HavePrefix(`"/now/slang_date": `))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return false if there is no schema for the endpoint in JsonSchema mode", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.JsonSchema,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					JsonSchemas:           []string{"GET /users/*=test_fixtures/document-a-schema.json"},
+				}
+				Expect(conf.CompileJsonSchemas()).Should(Succeed())
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080/now")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.SchemaDiff).Should(Equal(`"": not validated, there is no JSON Schema configured for GET /now`))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should fail to load JSON Schemas with invalid definitions or schemas", func() {
+				invalidDefinition := &core.DiferenciaConfiguration{JsonSchemas: []string{"GET /users/*"}}
+				missingSchema := &core.DiferenciaConfiguration{JsonSchemas: []string{"GET /users/*=test_fixtures/not-found.json"}}
+				missingGlobalSchema := &core.DiferenciaConfiguration{JsonSchema: "test_fixtures/not-found.json"}
+
+				Expect(invalidDefinition.CompileJsonSchemas()).Should(HaveOccurred())
+				Expect(missingSchema.CompileJsonSchemas()).Should(HaveOccurred())
+				Expect(missingGlobalSchema.CompileJsonSchemas()).Should(HaveOccurred())
+			})
+
+			It("should return false if documents are equal but candidate is not valid against schema", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					JsonSchema:            "test_fixtures/document-a-schema.json",
+				}
+				Expect(conf.CompileJsonSchemas()).Should(Succeed())
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(len(result.Diff.SchemaDiff)).Should(BeNumerically(">", 0))
+				Expect(err).Should(Succeed())
+			})
+			It("should not validate candidate against schema if it is not a JSON document", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/greeting.txt", "test_fixtures/greeting.txt")
+				recordStatus(httpClient, 200, 200)
+				textHeader := http.Header{}
+				textHeader.Set("Content-Type", "text/plain")
+				recordHeader(httpClient, textHeader, textHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					JsonSchema:            "test_fixtures/document-a-schema.json",
+				}
+				Expect(conf.CompileJsonSchemas()).Should(Succeed())
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(result.Diff.SchemaDiff).Should(BeEmpty())
+				Expect(err).Should(Succeed())
+			})
+		})
+
+		Context("With incorrect configuration", func() {
+			It("should return error if safe enabled and unsafe operation", func() {
+
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/document-a.json", "test_fixtures/document-a.json")
+				recordStatus(httpClient, 200, 200)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodPost, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(err).Should(HaveOccurred())
+			})
+		})
+
+		Context("With XML documents", func() {
+			It("should return true if XML documents are equal after removing noise", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/envelope.xml", "test_fixtures/envelope-candidate.xml", "test_fixtures/envelope-secondary.xml")
+				recordStatus(httpClient, 200, 200, 200)
+				xmlHeader := http.Header{}
+				xmlHeader.Set("Content-Type", "application/soap+xml; charset=utf-8")
+				recordHeader(httpClient, xmlHeader, xmlHeader, xmlHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return false with XPath of differences if XML documents are different", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/envelope.xml", "test_fixtures/envelope-candidate.xml")
+				recordStatus(httpClient, 200, 200)
+				xmlHeader := http.Header{}
+				xmlHeader.Set("Content-Type", "text/xml")
+				recordHeader(httpClient, xmlHeader, xmlHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					IgnoreXPaths:          []string{"//@*[local-name()='requestId']"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyDiff).Should(HaveSuffix(`/*[local-name()='Timestamp' and namespace-uri()='http://diferencia.io/prices']/text()": "2018-06-18T11:46:23.873849Z" => "2018-06-18T13:45:05.830951Z"`))
+				Expect(err).Should(Succeed())
+			})
+		})
+
+		Context("With form documents", func() {
+			It("should return false with field differences if forms are different", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/form-a.txt", "test_fixtures/form-a-changed.txt")
+				recordStatus(httpClient, 200, 200)
+				formHeader := http.Header{}
+				formHeader.Set("Content-Type", "application/x-www-form-urlencoded")
+				recordHeader(httpClient, formHeader, formHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyDiff).Should(ContainSubstring(`"roles": ["admin", "user"] => ["user"]`))
+				Expect(err).Should(Succeed())
+			})
+		})
+
+		Context("With HTML documents", func() {
+			It("should return true if HTML documents are equal after removing noise", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/page-a.html", "test_fixtures/page-a-candidate.html", "test_fixtures/page-a-secondary.html")
+				recordStatus(httpClient, 200, 200, 200)
+				htmlHeader := http.Header{}
+				htmlHeader.Set("Content-Type", "text/html; charset=utf-8")
+				recordHeader(httpClient, htmlHeader, htmlHeader, htmlHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return false if HTML documents are different", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/page-a.html", "test_fixtures/page-a-changed.html")
+				recordStatus(httpClient, 200, 200)
+				htmlHeader := http.Header{}
+				htmlHeader.Set("Content-Type", "text/html")
+				recordHeader(httpClient, htmlHeader, htmlHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					IgnoreSelectors:       []string{"script@nonce", "input[name=csrf]", "p::text"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyDiff).Should(ContainSubstring("li:nth-of-type(2)::text"))
+				Expect(err).Should(Succeed())
+			})
+		})
+
+		Context("With YAML documents", func() {
+			It("should return true if YAML documents are equal after removing noise and ignored values", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/config-a.yaml", "test_fixtures/config-a-candidate.yaml", "test_fixtures/config-a-secondary.yaml")
+				recordStatus(httpClient, 200, 200, 200)
+				yamlHeader := http.Header{}
+				yamlHeader.Set("Content-Type", "application/x-yaml")
+				recordHeader(httpClient, yamlHeader, yamlHeader, yamlHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					IgnoreValues:          []string{"/database/host"},
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return false if YAML documents are different", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/config-a.yaml", "test_fixtures/config-a-candidate.yaml")
+				recordStatus(httpClient, 200, 200)
+				yamlHeader := http.Header{}
+				yamlHeader.Set("Content-Type", "application/yaml")
+				recordHeader(httpClient, yamlHeader, yamlHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					BodyDiffFormat:        core.PatchBodyDiff,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyPatch).Should(HaveLen(2))
+				Expect(result.Diff.BodyPatch[0].Path).Should(Equal("/database/host"))
+				Expect(result.Diff.BodyPatch[1].Path).Should(Equal("/generated"))
+				Expect(err).Should(Succeed())
+			})
+		})
+
+		Context("With plain text documents", func() {
+			It("should return true with charset difference if texts are equal after decoding them with their charset", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/dessert-latin1.txt", "test_fixtures/dessert.txt")
+				recordStatus(httpClient, 200, 200)
+				latin1Header := http.Header{}
+				latin1Header.Set("Content-Type", "text/plain; charset=ISO-8859-1")
+				utf8Header := http.Header{}
+				utf8Header.Set("Content-Type", "text/plain; charset=utf-8")
+				recordHeader(httpClient, latin1Header, utf8Header)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					LevenshteinPercentage: 100,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(result.Diff.BodyDiff).Should(Equal(""))
+				Expect(result.Diff.CharsetDiff).Should(Equal(`"charset": "iso-8859-1" => "utf-8"`))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should remove noise from texts decoded with their charset", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/order-latin1.txt", "test_fixtures/order-candidate.txt", "test_fixtures/order-secondary-latin1.txt")
+				recordStatus(httpClient, 200, 200, 200)
+				latin1Header := http.Header{}
+				latin1Header.Set("Content-Type", "text/plain; charset=ISO-8859-1")
+				utf8Header := http.Header{}
+				utf8Header.Set("Content-Type", "text/plain; charset=utf-8")
+				recordHeader(httpClient, latin1Header, utf8Header, latin1Header)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Secondary:             "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        true,
+					AllowUnsafeOperations: false,
+					LevenshteinPercentage: 100,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(result.Diff.BodyDiff).Should(Equal(""))
+				Expect(result.Diff.CharsetDiff).Should(Equal(`"charset": "iso-8859-1" => "utf-8"`))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return true if texts are equal after masking ignored text patterns", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/request-log.txt", "test_fixtures/request-log-candidate.txt")
+				recordStatus(httpClient, 200, 200)
+				textHeader := http.Header{}
+				textHeader.Set("Content-Type", "text/plain")
+				recordHeader(httpClient, textHeader, textHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                   8080,
+					Primary:                "http://now.httpbin.org/",
+					Candidate:              "http://now.httpbin.org/",
+					StoreResults:           "",
+					DifferenceMode:         core.Strict,
+					NoiseDetection:         false,
+					AllowUnsafeOperations:  false,
+					LevenshteinPercentage:  100,
+					IgnoreTextPatternsFile: "test_fixtures/ignore_text_patterns.txt",
+				}
+				core.Config = conf
+				Expect(conf.CompileIgnoreTextPatterns()).Should(Succeed())
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should read ignored text patterns file only when patterns are compiled", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/request-log.txt", "test_fixtures/request-log-candidate.txt")
+				recordStatus(httpClient, 200, 200)
+				textHeader := http.Header{}
+				textHeader.Set("Content-Type", "text/plain")
+				recordHeader(httpClient, textHeader, textHeader)
+				core.HttpClient = httpClient
+
+				patternsFile, err := ioutil.TempFile("", "ignore_text_patterns.txt")
+				Expect(err).Should(Succeed())
+				defer os.Remove(patternsFile.Name())
+				patternsFile.WriteString(loadFromFile("test_fixtures/ignore_text_patterns.txt"))
+				patternsFile.Close()
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                   8080,
+					Primary:                "http://now.httpbin.org/",
+					Candidate:              "http://now.httpbin.org/",
+					StoreResults:           "",
+					DifferenceMode:         core.Strict,
+					NoiseDetection:         false,
+					AllowUnsafeOperations:  false,
+					LevenshteinPercentage:  100,
+					IgnoreTextPatternsFile: patternsFile.Name(),
+				}
+				core.Config = conf
+				Expect(conf.CompileIgnoreTextPatterns()).Should(Succeed())
+				os.Remove(patternsFile.Name())
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return true if texts are similar using configured text similarity", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/greeting.txt", "test_fixtures/greeting-changed.txt")
+				recordStatus(httpClient, 200, 200)
+				textHeader := http.Header{}
+				textHeader.Set("Content-Type", "text/plain")
+				recordHeader(httpClient, textHeader, textHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					LevenshteinPercentage: 100,
+					TextSimilarity:        "Cosine=80",
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(true))
+				Expect(err).Should(Succeed())
+			})
+
+			It("should return unified diff and similarity if texts are different", func() {
+				// Given
+				var httpClient = &StubHttpClient{}
+				// Record Http Client responses
+				recordContent(httpClient, "test_fixtures/greeting.txt", "test_fixtures/greeting-changed.txt")
+				recordStatus(httpClient, 200, 200)
+				textHeader := http.Header{}
+				textHeader.Set("Content-Type", "text/plain")
+				recordHeader(httpClient, textHeader, textHeader)
+				core.HttpClient = httpClient
+
+				// Prepare Configuration object
+				conf := &core.DiferenciaConfiguration{
+					Port:                  8080,
+					Primary:               "http://now.httpbin.org/",
+					Candidate:             "http://now.httpbin.org/",
+					StoreResults:          "",
+					DifferenceMode:        core.Strict,
+					NoiseDetection:        false,
+					AllowUnsafeOperations: false,
+					LevenshteinPercentage: 99,
+				}
+				core.Config = conf
+
+				// Create stubbed http.Request object
+				url, _ := url.Parse("http://localhost:8080")
+				request := createRequest(http.MethodGet, url)
+
+				// When
+
+				result, _, err := core.Diferencia(&request)
+
+				//Then
+
+				Expect(result.EqualContent).Should(Equal(false))
+				Expect(result.Diff.BodyDiff).Should(HavePrefix("Levenshtein similarity: "))
+				Expect(result.Diff.BodyDiff).Should(ContainSubstring("--- primary\n+++ candidate\n@@ -1,3 +1,3 @@\n Hello Alex\n-Your order is ready\n+Your order is delayed\n Thank you for trusting us"))
+				Expect(err).Should(Succeed())
+			})
 		})
 
 		Context("With Headers check", func() {
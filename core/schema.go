@@ -0,0 +1,138 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/lordofthejars/diferencia/difference/json"
+	"github.com/sirupsen/logrus"
+)
+
+// jsonSchemaEndpoint binds the validator of a JSON Schema to the endpoints it is used for
+type jsonSchemaEndpoint struct {
+	endpoint  endpointPattern
+	validator *json.SchemaValidator
+}
+
+// CompileJsonSchemas parses the JSON Schema definitions per endpoint and loads the global and per endpoint schemas,
+// so they are only loaded once and invalid definitions or schemas are reported when Diferencia starts
+func (conf *DiferenciaConfiguration) CompileJsonSchemas() error {
+	validators := make(map[string]*json.SchemaValidator)
+	var endpoints []jsonSchemaEndpoint
+
+	for _, definition := range conf.JsonSchemas {
+		endpoint, location, err := parseJsonSchemaDefinition(definition)
+		if err != nil {
+			return err
+		}
+
+		validator, err := loadSchemaValidator(location, validators)
+		if err != nil {
+			return err
+		}
+
+		endpoints = append(endpoints, jsonSchemaEndpoint{endpoint: endpoint, validator: validator})
+	}
+
+	if len(conf.JsonSchema) > 0 {
+		validator, err := loadSchemaValidator(conf.JsonSchema, validators)
+		if err != nil {
+			return err
+		}
+
+		// Global schema matches any endpoint, so it is used by the ones without their own schema
+		endpoints = append(endpoints, jsonSchemaEndpoint{validator: validator})
+	}
+
+	conf.jsonSchemaEndpoints = endpoints
+	return nil
+}
+
+// validateJsonSchema validates candidate content against the JSON Schema configured for the endpoint.
+// If there is no schema configured, content is considered valid, except in JsonSchema mode where candidate is not compared with primary,
+// so it is reported as not validated.
+func validateJsonSchema(r *http.Request, candidate []byte) (bool, string) {
+
+	schema, ok := findJsonSchema(r.Method, r.URL.Path)
+
+	if !ok {
+		if Config.DifferenceMode != JsonSchema {
+			return true, ""
+		}
+
+		logrus.Warnf("There is no JSON Schema configured for %s %s, so candidate content is not validated.", r.Method, r.URL.Path)
+		return false, fmt.Sprintf(`"": not validated, there is no JSON Schema configured for %s %s`, r.Method, r.URL.Path)
+	}
+
+	return schema.validator.Validate(candidate)
+}
+
+// findJsonSchema returns the schema of the first endpoint definition matching method and path or the global one
+func findJsonSchema(method, requestPath string) (jsonSchemaEndpoint, bool) {
+
+	for _, schema := range Config.jsonSchemaEndpoints {
+		if schema.endpoint.matches(method, requestPath) {
+			return schema, true
+		}
+	}
+
+	return jsonSchemaEndpoint{}, false
+}
+
+// endpointPattern matches requests by method and path pattern. An empty pattern matches any request.
+type endpointPattern struct {
+	method string
+	path   string
+}
+
+func (e endpointPattern) matches(method, requestPath string) bool {
+	if len(e.path) == 0 {
+		return true
+	}
+
+	if len(e.method) > 0 && !strings.EqualFold(e.method, method) {
+		return false
+	}
+
+	matched, _ := path.Match(e.path, requestPath)
+	return matched
+}
+
+// parseJsonSchemaDefinition parses an endpoint definition following the format [METHOD ]pathPattern=schemaLocation for example GET /users/*=users.json
+func parseJsonSchemaDefinition(definition string) (endpointPattern, string, error) {
+
+	separator := strings.LastIndex(definition, "=")
+
+	if separator <= 0 || separator == len(definition)-1 {
+		return endpointPattern{}, "", fmt.Errorf("JSON Schema definition %s does not follow [METHOD ]pathPattern=schemaLocation format", definition)
+	}
+
+	endpoint := strings.Fields(definition[:separator])
+	location := strings.TrimSpace(definition[separator+1:])
+
+	switch len(endpoint) {
+	case 1:
+		return endpointPattern{path: endpoint[0]}, location, nil
+	case 2:
+		return endpointPattern{method: endpoint[0], path: endpoint[1]}, location, nil
+	}
+
+	return endpointPattern{}, "", fmt.Errorf("JSON Schema definition %s does not follow [METHOD ]pathPattern=schemaLocation format", definition)
+}
+
+func loadSchemaValidator(location string, validators map[string]*json.SchemaValidator) (*json.SchemaValidator, error) {
+
+	if validator, ok := validators[location]; ok {
+		return validator, nil
+	}
+
+	validator, err := json.NewSchemaValidator(location)
+	if err != nil {
+		return nil, fmt.Errorf("JSON Schema %s cannot be loaded. %s", location, err.Error())
+	}
+
+	validators[location] = validator
+	return validator, nil
+}
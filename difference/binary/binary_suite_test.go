@@ -0,0 +1,13 @@
+package binary_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiferenciaBinary(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diferencia Binary Suite")
+}
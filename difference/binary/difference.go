@@ -0,0 +1,57 @@
+package binary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Digest returns the SHA-256 digest of the content in hex format
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// CompareDocuments comparing two binary documents by their SHA-256 digest and size.
+// It returns the differences in the form of `"field": primary => candidate` together with the offset of the first different byte.
+func CompareDocuments(candidate, original []byte) (bool, string) {
+
+	originalDigest := Digest(original)
+	candidateDigest := Digest(candidate)
+
+	if originalDigest == candidateDigest && len(original) == len(candidate) {
+		return true, ""
+	}
+
+	lines := []string{fmt.Sprintf(`"sha256": %q => %q`, originalDigest, candidateDigest)}
+
+	if len(original) != len(candidate) {
+		lines = append(lines, fmt.Sprintf(`"size": %d => %d`, len(original), len(candidate)))
+	}
+
+	lines = append(lines, fmt.Sprintf(`"offset": %d`, FirstDifferentByte(candidate, original)))
+
+	return false, strings.Join(lines, "\n")
+}
+
+// FirstDifferentByte returns the offset of the first byte that is different between both documents.
+// If one document is a prefix of the other, the offset is the size of the shortest one, and -1 if both are equal.
+func FirstDifferentByte(candidate, original []byte) int {
+	shortest := len(original)
+	if len(candidate) < shortest {
+		shortest = len(candidate)
+	}
+
+	for i := 0; i < shortest; i++ {
+		if candidate[i] != original[i] {
+			return i
+		}
+	}
+
+	if len(candidate) == len(original) {
+		return -1
+	}
+
+	return shortest
+}
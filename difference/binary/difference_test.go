@@ -0,0 +1,52 @@
+package binary_test
+
+import (
+	"github.com/lordofthejars/diferencia/difference/binary"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Binary Difference", func() {
+
+	var document = []byte{0x25, 0x50, 0x44, 0x46, 0x2d, 0x31, 0x2e, 0x34, 0x00, 0xff}
+
+	Describe("Compare binary documents", func() {
+		Context("With equal documents", func() {
+			It("should return true", func() {
+				equal, diff := binary.CompareDocuments(document, []byte{0x25, 0x50, 0x44, 0x46, 0x2d, 0x31, 0x2e, 0x34, 0x00, 0xff})
+
+				Expect(equal).Should(Equal(true))
+				Expect(diff).Should(BeEmpty())
+			})
+		})
+
+		Context("With different documents", func() {
+			It("should report digests and first different byte if sizes are equal", func() {
+				candidate := []byte{0x25, 0x50, 0x44, 0x46, 0x2d, 0x31, 0x2e, 0x35, 0x00, 0xff}
+
+				equal, diff := binary.CompareDocuments(candidate, document)
+
+				Expect(equal).Should(Equal(false))
+				Expect(diff).Should(Equal(`"sha256": "` + binary.Digest(document) + `" => "` + binary.Digest(candidate) + `"` + "\n" + `"offset": 7`))
+			})
+
+			It("should report sizes if candidate is truncated", func() {
+				equal, diff := binary.CompareDocuments(document[:4], document)
+
+				Expect(equal).Should(Equal(false))
+				Expect(diff).Should(ContainSubstring(`"size": 10 => 4`))
+				Expect(diff).Should(ContainSubstring(`"offset": 4`))
+			})
+		})
+
+		Context("Finding first different byte", func() {
+			It("should return -1 if documents are equal", func() {
+				Expect(binary.FirstDifferentByte(document, document)).Should(Equal(-1))
+			})
+
+			It("should return the offset of the first different byte", func() {
+				Expect(binary.FirstDifferentByte([]byte{0x00, 0x01, 0x02}, []byte{0x00, 0x01, 0x03})).Should(Equal(2))
+			})
+		})
+	})
+})
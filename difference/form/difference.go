@@ -0,0 +1,101 @@
+package form
+
+import (
+	"fmt"
+	"strings"
+)
+
+const undefinedValue = "undefined"
+
+// CompareDocuments comparing two forms and returns true or false according to configured difference.
+// Each form is parsed according to its own content type, so for example multipart boundaries might be different.
+// It returns the list of differences in the form of `"field": primary values => candidate values`
+func CompareDocuments(candidate, original []byte, candidateContentType, originalContentType, difference string) (bool, string) {
+
+	candidateForm, err := Parse(candidate, candidateContentType)
+	if err != nil {
+		return false, fmt.Sprintf("first argument is invalid form. %s", err.Error())
+	}
+
+	originalForm, err := Parse(original, originalContentType)
+	if err != nil {
+		return false, fmt.Sprintf("second argument is invalid form. %s", err.Error())
+	}
+
+	differences := CompareForms(candidateForm, originalForm, difference)
+
+	return len(differences) == 0, strings.Join(differences, "\n")
+}
+
+// CompareForms returns the fields with differences. Fields and repeated values are compared regardless of their order.
+// In Subset mode candidate might contain more fields and values, and in Schema mode only field names are compared.
+func CompareForms(candidate, original Form, difference string) []string {
+
+	var differences []string
+
+	for _, name := range unionOfNames(original, candidate) {
+		originalValues, inOriginal := original[name]
+		candidateValues, inCandidate := candidate[name]
+
+		switch {
+		case !inCandidate:
+			differences = append(differences, describeDifference(name, describeValues(original.sortedValues(name)), undefinedValue))
+		case !inOriginal:
+			if difference != "Subset" {
+				differences = append(differences, describeDifference(name, undefinedValue, describeValues(candidate.sortedValues(name))))
+			}
+		case difference == "Schema":
+			continue
+		case difference == "Subset" && containsAll(candidateValues, originalValues):
+			continue
+		case difference != "Subset" && equalValues(candidate.sortedValues(name), original.sortedValues(name)):
+			continue
+		default:
+			differences = append(differences, describeDifference(name, describeValues(original.sortedValues(name)), describeValues(candidate.sortedValues(name))))
+		}
+	}
+
+	return differences
+}
+
+func describeDifference(name, original, candidate string) string {
+	return fmt.Sprintf(`"%s": %s => %s`, name, original, candidate)
+}
+
+func unionOfNames(a, b Form) []string {
+	union := make(Form)
+	for name := range a {
+		union[name] = nil
+	}
+	for name := range b {
+		union[name] = nil
+	}
+	return union.names()
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAll checks that all values are present in container taking into consideration repeated values
+func containsAll(container, values []string) bool {
+	occurrences := make(map[string]int)
+	for _, value := range container {
+		occurrences[value]++
+	}
+	for _, value := range values {
+		if occurrences[value] == 0 {
+			return false
+		}
+		occurrences[value]--
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package form_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/form"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const urlEncoded = "application/x-www-form-urlencoded"
+
+var _ = Describe("Form Difference", func() {
+
+	Describe("Compare Two Url Encoded forms", func() {
+		Context("With strict mode", func() {
+			It("should return that are equal when only order of fields changes", func() {
+				formA := loadFromFile("test_fixtures/form-a.txt")
+				formB := loadFromFile("test_fixtures/form-a-reordered.txt")
+
+				result, output := form.CompareDocuments(formB, formA, urlEncoded, urlEncoded, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return field differences", func() {
+				formA := loadFromFile("test_fixtures/form-a.txt")
+				formB := loadFromFile("test_fixtures/form-a-changed.txt")
+
+				result, output := form.CompareDocuments(formB, formA, urlEncoded, urlEncoded, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(Equal(`"email": undefined => ["alex@example.com"]` + "\n" + `"roles": ["admin", "user"] => ["user"]`))
+			})
+
+			It("should return that are different when content is not a form", func() {
+				formA := loadFromFile("test_fixtures/form-a.txt")
+
+				result, output := form.CompareDocuments(formA, formA, "application/json", urlEncoded, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring("first argument is invalid form"))
+			})
+		})
+
+		Context("With subset mode", func() {
+			It("should return that are different when a value is removed", func() {
+				formA := loadFromFile("test_fixtures/form-a.txt")
+				formB := loadFromFile("test_fixtures/form-a-changed.txt")
+
+				result, output := form.CompareDocuments(formB, formA, urlEncoded, urlEncoded, core.Subset.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(Equal(`"roles": ["admin", "user"] => ["user"]`))
+			})
+
+			It("should return that are equal when candidate contains more fields and values", func() {
+				formA := loadFromFile("test_fixtures/form-a.txt")
+				formB := loadFromFile("test_fixtures/form-a-superset.txt")
+
+				result, output := form.CompareDocuments(formB, formA, urlEncoded, urlEncoded, core.Subset.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+
+		Context("With schema mode", func() {
+			It("should return that are equal when only values change", func() {
+				formA := loadFromFile("test_fixtures/form-a.txt")
+				formB := loadFromFile("test_fixtures/form-a-other-token.txt")
+
+				result, _ := form.CompareDocuments(formB, formA, urlEncoded, urlEncoded, core.Schema.String())
+				Expect(result).To(Equal(true))
+			})
+		})
+	})
+
+	Describe("Compare Two Multipart forms", func() {
+		Context("With strict mode", func() {
+			It("should return that are equal when files have same content and boundaries are different", func() {
+				formA, contentTypeA := createMultipart(map[string]string{"name": "Alex"}, map[string]string{"avatar": "PNG content"})
+				formB, contentTypeB := createMultipart(map[string]string{"name": "Alex"}, map[string]string{"avatar": "PNG content"})
+
+				Expect(contentTypeA).ShouldNot(Equal(contentTypeB))
+				result, output := form.CompareDocuments(formB, formA, contentTypeB, contentTypeA, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return hash of files when files have different content", func() {
+				formA, contentTypeA := createMultipart(map[string]string{"name": "Alex"}, map[string]string{"avatar": "PNG content"})
+				formB, contentTypeB := createMultipart(map[string]string{"name": "Alex"}, map[string]string{"avatar": "JPG content"})
+
+				result, output := form.CompareDocuments(formB, formA, contentTypeB, contentTypeA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(HavePrefix(`"avatar": ["sha256:`))
+			})
+		})
+	})
+
+})
+
+func createMultipart(fields map[string]string, files map[string]string) ([]byte, string) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+
+	for name, content := range files {
+		part, _ := writer.CreateFormFile(name, name+".bin")
+		part.Write([]byte(content))
+	}
+
+	writer.Close()
+	return buffer.Bytes(), writer.FormDataContentType()
+}
+
+func loadFromFile(filePath string) []byte {
+	payload, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		Fail(fmt.Sprintf("Unable to load test fixture. Reason: %q", err))
+	}
+	return payload
+}
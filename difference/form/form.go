@@ -0,0 +1,150 @@
+package form
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	urlEncodedMediaType = "application/x-www-form-urlencoded"
+	multipartMediaType  = "multipart/form-data"
+	hashPrefix          = "sha256:"
+)
+
+// Form fields indexed by name. File parts of multipart forms are represented by the SHA-256 hash of their content.
+type Form map[string][]string
+
+// IsForm checks if content type is application/x-www-form-urlencoded or multipart/form-data
+func IsForm(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == urlEncodedMediaType || mediaType == multipartMediaType
+}
+
+// Parse form content according to its content type
+func Parse(content []byte, contentType string) (Form, error) {
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType {
+	case urlEncodedMediaType:
+		values, err := url.ParseQuery(string(content))
+		if err != nil {
+			return nil, err
+		}
+		return Form(values), nil
+	case multipartMediaType:
+		return parseMultipart(content, params["boundary"])
+	}
+
+	return nil, fmt.Errorf("Content type %s is not a form", contentType)
+}
+
+func parseMultipart(content []byte, boundary string) (Form, error) {
+
+	if len(boundary) == 0 {
+		return nil, fmt.Errorf("Multipart form does not define any boundary")
+	}
+
+	form := make(Form)
+	reader := multipart.NewReader(bytes.NewReader(content), boundary)
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(part.FileName()) > 0 {
+			hash := sha256.Sum256(value)
+			form[part.FormName()] = append(form[part.FormName()], hashPrefix+hex.EncodeToString(hash[:]))
+		} else {
+			form[part.FormName()] = append(form[part.FormName()], string(value))
+		}
+	}
+}
+
+// Encode form in the given content type. File parts of multipart forms are encoded as fields containing the hash of the file.
+func (form Form) Encode(contentType string) ([]byte, error) {
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType {
+	case urlEncodedMediaType:
+		return []byte(url.Values(form).Encode()), nil
+	case multipartMediaType:
+		var buffer bytes.Buffer
+		writer := multipart.NewWriter(&buffer)
+		if err := writer.SetBoundary(params["boundary"]); err != nil {
+			return nil, err
+		}
+		for _, name := range form.names() {
+			for _, value := range form[name] {
+				if err := writer.WriteField(name, value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("Content type %s is not a form", contentType)
+}
+
+func (form Form) names() []string {
+	var names []string
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedValues returns the values of the field in lexicographical order so the order of repeated fields is not taken into consideration
+func (form Form) sortedValues(name string) []string {
+	values := append([]string(nil), form[name]...)
+	sort.Strings(values)
+	return values
+}
+
+func (form Form) remove(names []string) {
+	for _, name := range names {
+		delete(form, name)
+	}
+}
+
+func describeValues(values []string) string {
+	var quoted []string
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
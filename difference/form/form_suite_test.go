@@ -0,0 +1,13 @@
+package form_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiferenciaForm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diferencia Form Suite")
+}
@@ -0,0 +1,68 @@
+package form
+
+// NoiseOperation struct
+type NoiseOperation struct {
+	// Fields considered noise
+	Fields []string
+}
+
+// Initialize with some field names
+func (nd *NoiseOperation) Initialize(fields []string) {
+	nd.Fields = append(nd.Fields, fields...)
+}
+
+// ContainsNoise method
+func (nd NoiseOperation) ContainsNoise() bool {
+	return len(nd.Fields) > 0
+}
+
+// Detect Noise between forms. Fields with different values or only present in one of the forms are considered noise.
+func (nd *NoiseOperation) Detect(primary, secondary []byte, primaryContentType, secondaryContentType string) error {
+
+	primaryForm, err := Parse(primary, primaryContentType)
+	if err != nil {
+		return err
+	}
+
+	secondaryForm, err := Parse(secondary, secondaryContentType)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range unionOfNames(primaryForm, secondaryForm) {
+		if !equalValues(primaryForm.sortedValues(name), secondaryForm.sortedValues(name)) {
+			nd.Fields = append(nd.Fields, name)
+		}
+	}
+
+	return nil
+}
+
+// Remove noise from primary and candidate forms. Forms are encoded again using their content type.
+func (nd *NoiseOperation) Remove(primary, candidate []byte, primaryContentType, candidateContentType string) ([]byte, []byte, error) {
+
+	primaryForm, err := Parse(primary, primaryContentType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateForm, err := Parse(candidate, candidateContentType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	primaryForm.remove(nd.Fields)
+	candidateForm.remove(nd.Fields)
+
+	primaryWithoutNoise, err := primaryForm.Encode(primaryContentType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateWithoutNoise, err := candidateForm.Encode(candidateContentType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return primaryWithoutNoise, candidateWithoutNoise, nil
+}
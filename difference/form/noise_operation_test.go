@@ -0,0 +1,48 @@
+package form_test
+
+import (
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/form"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Form Noise Operation", func() {
+
+	Describe("Detect noise", func() {
+		Context("With forms with different values", func() {
+			It("should detect fields as noise", func() {
+				primary := loadFromFile("test_fixtures/form-a.txt")
+				secondary := loadFromFile("test_fixtures/form-a-other-token.txt")
+
+				noiseOperation := form.NoiseOperation{}
+				err := noiseOperation.Detect(primary, secondary, urlEncoded, urlEncoded)
+
+				Expect(err).Should(Succeed())
+				Expect(noiseOperation.Fields).Should(Equal([]string{"token"}))
+			})
+		})
+	})
+
+	Describe("Remove noise", func() {
+		Context("With multipart forms", func() {
+			It("should return forms without noisy fields", func() {
+				primary, primaryContentType := createMultipart(map[string]string{"name": "Alex", "token": "a1b2c3"}, map[string]string{"avatar": "PNG content"})
+				secondary, secondaryContentType := createMultipart(map[string]string{"name": "Alex", "token": "d4e5f6"}, map[string]string{"avatar": "PNG content"})
+				candidate, candidateContentType := createMultipart(map[string]string{"name": "Alex", "token": "g7h8i9"}, map[string]string{"avatar": "PNG content"})
+
+				noiseOperation := form.NoiseOperation{}
+				noiseOperation.Detect(primary, secondary, primaryContentType, secondaryContentType)
+				primaryWithoutNoise, candidateWithoutNoise, err := noiseOperation.Remove(primary, candidate, primaryContentType, candidateContentType)
+
+				Expect(err).Should(Succeed())
+				result, output := form.CompareDocuments(candidateWithoutNoise, primaryWithoutNoise, candidateContentType, primaryContentType, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+	})
+
+})
@@ -0,0 +1,152 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+)
+
+const undefinedValue = "undefined"
+
+// difference between primary and candidate document located by a CSS selector
+type difference struct {
+	path      string
+	primary   string
+	candidate string
+	// structural differences are elements or attributes present only in one of the documents
+	structural bool
+}
+
+func (d difference) String() string {
+	return fmt.Sprintf(`"%s": %s => %s`, d.path, d.primary, d.candidate)
+}
+
+// CompareDocuments comparing two HTML documents and returns true or false according to configured difference
+func CompareDocuments(candidate, original []byte, difference string) (bool, string) {
+	return CompareDocumentsIgnoring(candidate, original, difference, nil)
+}
+
+// CompareDocumentsIgnoring comparing two HTML documents without taking into consideration the nodes selected by the rules.
+// Each rule is a CSS selector, optionally followed by @attribute or ::text to ignore only an attribute or the text of the selected elements.
+// In Schema mode, only elements and attributes present in one of the documents are reported.
+// It returns the list of differences in the form of `"path": primary => candidate`
+func CompareDocumentsIgnoring(candidate, original []byte, difference string, ignoredNodes []string) (bool, string) {
+
+	rules, err := compileRules(ignoredNodes)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	candidateDocument, err := prepare(candidate, rules)
+	if err != nil {
+		return false, fmt.Sprintf("first argument is invalid html. %s", err.Error())
+	}
+
+	originalDocument, err := prepare(original, rules)
+	if err != nil {
+		return false, fmt.Sprintf("second argument is invalid html. %s", err.Error())
+	}
+
+	differences := compareDocuments(originalDocument, candidateDocument, difference == "Subset")
+
+	var lines []string
+	for _, d := range differences {
+		if difference == "Schema" && !d.structural {
+			continue
+		}
+		lines = append(lines, d.String())
+	}
+
+	return len(lines) == 0, strings.Join(lines, "\n")
+}
+
+func compareDocuments(original, candidate *element, subset bool) []difference {
+	var differences []difference
+	compareElements(original, candidate, original.tag, subset, &differences)
+	return differences
+}
+
+// compareElements compares attributes, text and children. Children are matched by tag and position between siblings with same tag.
+// In subset mode, attributes and children only present in candidate are not reported.
+func compareElements(original, candidate *element, path string, subset bool, differences *[]difference) {
+
+	for _, key := range sortedKeys(original.attributes) {
+		candidateValue, ok := candidate.attributes[key]
+		switch {
+		case !ok:
+			*differences = append(*differences, difference{path: path + "@" + key, primary: fmt.Sprintf("%q", original.attributes[key]), candidate: undefinedValue, structural: true})
+		case candidateValue != original.attributes[key]:
+			*differences = append(*differences, difference{path: path + "@" + key, primary: fmt.Sprintf("%q", original.attributes[key]), candidate: fmt.Sprintf("%q", candidateValue)})
+		}
+	}
+
+	if !subset {
+		for _, key := range sortedKeys(candidate.attributes) {
+			if _, ok := original.attributes[key]; !ok {
+				*differences = append(*differences, difference{path: path + "@" + key, primary: undefinedValue, candidate: fmt.Sprintf("%q", candidate.attributes[key]), structural: true})
+			}
+		}
+	}
+
+	if original.text != candidate.text {
+		*differences = append(*differences, difference{path: path + textSuffix, primary: fmt.Sprintf("%q", original.text), candidate: fmt.Sprintf("%q", candidate.text)})
+	}
+
+	originalChildren := groupByTag(original.children)
+	candidateChildren := groupByTag(candidate.children)
+
+	for _, tag := range unionOfTags(original.children, candidate.children) {
+		originalGroup := originalChildren[tag]
+		candidateGroup := candidateChildren[tag]
+		siblings := maximum(len(originalGroup), len(candidateGroup))
+
+		for i := 0; i < siblings; i++ {
+			childPath := path + " > " + elementStep(tag, i+1, siblings)
+			switch {
+			case i < len(originalGroup) && i < len(candidateGroup):
+				compareElements(originalGroup[i], candidateGroup[i], childPath, subset, differences)
+			case i < len(originalGroup):
+				*differences = append(*differences, difference{path: childPath, primary: "<" + tag + ">", candidate: undefinedValue, structural: true})
+			case !subset:
+				*differences = append(*differences, difference{path: childPath, primary: undefinedValue, candidate: "<" + tag + ">", structural: true})
+			}
+		}
+	}
+}
+
+func groupByTag(elements []*element) map[string][]*element {
+	groups := make(map[string][]*element)
+	for _, e := range elements {
+		groups[e.tag] = append(groups[e.tag], e)
+	}
+	return groups
+}
+
+// unionOfTags returns the tags of the elements in order of appearance, first the ones of original and then the ones only present in candidate
+func unionOfTags(original, candidate []*element) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, elements := range [][]*element{original, candidate} {
+		for _, e := range elements {
+			if !seen[e.tag] {
+				seen[e.tag] = true
+				tags = append(tags, e.tag)
+			}
+		}
+	}
+	return tags
+}
+
+// elementStep returns the CSS selector step of the element. Position is only added when there is more than one sibling with same tag.
+func elementStep(tag string, position, siblings int) string {
+	if siblings > 1 {
+		return fmt.Sprintf("%s:nth-of-type(%d)", tag, position)
+	}
+	return tag
+}
+
+func maximum(x, y int) int {
+	if x < y {
+		return y
+	}
+	return x
+}
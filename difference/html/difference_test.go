@@ -0,0 +1,113 @@
+package html_test
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/html"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Html Difference", func() {
+
+	Describe("Compare Two Equal Html documents", func() {
+		Context("With strict mode", func() {
+			It("should return that are equal when only whitespaces, comments, attributes order or classes order change", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+				documentB := loadFromFile("test_fixtures/page-a-reflow.html")
+
+				result, output := html.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Compare Two Different Html documents", func() {
+		Context("With strict mode", func() {
+			It("should return differences as element paths", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+				documentB := loadFromFile("test_fixtures/page-a-changed.html")
+
+				result, output := html.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(Equal(`"html > head > script@nonce": "r4nd0m1" => "r4nd0m3"` + "\n" +
+					`"html > body > div > form > input:nth-of-type(1)@value": "a1b2c3" => "g7h8i9"` + "\n" +
+					`"html > body > div > ul > li:nth-of-type(2)::text": "Pencil" => "Pen"` + "\n" +
+					`"html > body > div > p::text": "Generated at 11:46:23" => "Generated at 13:45:05"`))
+			})
+
+			It("should return that are equal when different nodes are ignored", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+				documentB := loadFromFile("test_fixtures/page-a-changed.html")
+
+				result, output := html.CompareDocumentsIgnoring(documentB, documentA, core.Strict.String(), []string{"script@nonce", "input[name=csrf]", "p::text", "ul"})
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when ignore rule is not valid", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+
+				result, output := html.CompareDocumentsIgnoring(documentA, documentA, core.Strict.String(), []string{"div["})
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring("CSS selector div[ is not valid"))
+			})
+
+			It("should return that are different when candidate contains more elements", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+				documentB := loadFromFile("test_fixtures/page-a-superset.html")
+
+				result, output := html.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(Equal(`"html > body > div > ul > li:nth-of-type(3)": undefined => <li>`))
+			})
+		})
+
+		Context("With subset mode", func() {
+			It("should return that are equal when candidate contains more elements", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+				documentB := loadFromFile("test_fixtures/page-a-superset.html")
+
+				result, output := html.CompareDocuments(documentB, documentA, core.Subset.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+
+		Context("With schema mode", func() {
+			It("should return that are equal when only values change", func() {
+				documentA := loadFromFile("test_fixtures/page-a.html")
+				documentB := loadFromFile("test_fixtures/page-a-changed.html")
+
+				result, _ := html.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(true))
+			})
+		})
+	})
+
+	Describe("Validating CSS selectors", func() {
+		It("should accept valid expressions", func() {
+			for _, expression := range []string{"div.ads", "img@src", "#clock::text"} {
+				Expect(html.ValidateSelector(expression)).Should(Succeed())
+			}
+		})
+
+		It("should fail with invalid expressions", func() {
+			Expect(html.ValidateSelector("div[")).Should(HaveOccurred())
+		})
+	})
+
+})
+
+func loadFromFile(filePath string) []byte {
+	payload, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		Fail(fmt.Sprintf("Unable to load test fixture. Reason: %q", err))
+	}
+	return payload
+}
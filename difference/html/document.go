@@ -0,0 +1,191 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+const textSuffix = "::text"
+
+var attributeName = regexp.MustCompile(`^[A-Za-z_:][-A-Za-z0-9_:.]*$`)
+
+// element is the normalized representation of an HTML element where comments, attributes order, classes order and whitespaces are not taken into consideration
+type element struct {
+	tag        string
+	attributes map[string]string
+	text       string
+	children   []*element
+}
+
+// IsHtml checks if content type is text/html
+func IsHtml(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/html"
+}
+
+// rule selects the nodes to ignore. It is a CSS selector selecting elements, optionally followed by @attribute to select only an attribute
+// or by ::text to select only the text of the elements.
+type rule struct {
+	selector  cascadia.Selector
+	attribute string
+	text      bool
+}
+
+// ValidateSelector checks that the CSS selector, optionally followed by @attribute or ::text, is valid
+func ValidateSelector(expression string) error {
+	_, err := compileRules([]string{expression})
+	return err
+}
+
+func compileRules(expressions []string) ([]rule, error) {
+
+	var rules []rule
+
+	for _, expression := range expressions {
+		selector, attribute, text := splitRule(expression)
+		compiled, err := cascadia.Compile(selector)
+		if err != nil {
+			return nil, fmt.Errorf("CSS selector %s is not valid. %s", expression, err.Error())
+		}
+		rules = append(rules, rule{selector: compiled, attribute: attribute, text: text})
+	}
+
+	return rules, nil
+}
+
+func splitRule(expression string) (string, string, bool) {
+	expression = strings.TrimSpace(expression)
+
+	if strings.HasSuffix(expression, textSuffix) {
+		return strings.TrimSuffix(expression, textSuffix), "", true
+	}
+
+	// CSS selectors never contain @ outside of quoted values, so the attribute is only taken if it is a valid attribute name
+	if separator := strings.LastIndex(expression, "@"); separator > 0 && attributeName.MatchString(expression[separator+1:]) {
+		return expression[:separator], strings.ToLower(expression[separator+1:]), false
+	}
+
+	return expression, "", false
+}
+
+// removeNodes removes from document all elements, attributes or texts selected by any of the rules
+func removeNodes(document *html.Node, rules []rule) {
+
+	for _, rule := range rules {
+		for _, node := range rule.selector.MatchAll(document) {
+			switch {
+			case rule.text:
+				removeText(node)
+			case len(rule.attribute) > 0:
+				removeAttribute(node, rule.attribute)
+			case node.Parent != nil:
+				node.Parent.RemoveChild(node)
+			}
+		}
+	}
+}
+
+func removeText(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.TextNode {
+			node.RemoveChild(child)
+		}
+		child = next
+	}
+}
+
+func removeAttribute(node *html.Node, name string) {
+	var attributes []html.Attribute
+	for _, attribute := range node.Attr {
+		if attribute.Key != name {
+			attributes = append(attributes, attribute)
+		}
+	}
+	node.Attr = attributes
+}
+
+// prepare parses the document, removes the nodes selected by the rules and returns the normalized root element
+func prepare(document []byte, rules []rule) (*element, error) {
+
+	parsed, err := html.Parse(bytes.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	removeNodes(parsed, rules)
+
+	for node := parsed.FirstChild; node != nil; node = node.NextSibling {
+		if node.Type == html.ElementNode {
+			return normalize(node, false), nil
+		}
+	}
+
+	return nil, fmt.Errorf("HTML document does not contain any root element")
+}
+
+func normalize(node *html.Node, preserveWhitespaces bool) *element {
+
+	current := &element{
+		tag:        node.Data,
+		attributes: make(map[string]string),
+	}
+
+	for _, attribute := range node.Attr {
+		key := attribute.Key
+		if len(attribute.Namespace) > 0 {
+			key = attribute.Namespace + ":" + key
+		}
+		current.attributes[key] = normalizeAttribute(key, attribute.Val)
+	}
+
+	preserveWhitespaces = preserveWhitespaces || node.Data == "pre" || node.Data == "textarea"
+
+	var text []string
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.ElementNode:
+			current.children = append(current.children, normalize(child, preserveWhitespaces))
+		case html.TextNode:
+			if preserveWhitespaces {
+				text = append(text, child.Data)
+			} else if normalized := strings.Join(strings.Fields(child.Data), " "); len(normalized) > 0 {
+				text = append(text, normalized)
+			}
+		}
+	}
+
+	if preserveWhitespaces {
+		current.text = strings.Join(text, "")
+	} else {
+		current.text = strings.Join(text, " ")
+	}
+
+	return current
+}
+
+// normalizeAttribute sorts classes since their order is not relevant
+func normalizeAttribute(key, value string) string {
+	if key == "class" {
+		classes := strings.Fields(value)
+		sort.Strings(classes)
+		return strings.Join(classes, " ")
+	}
+	return value
+}
+
+func sortedKeys(attributes map[string]string) []string {
+	var keys []string
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
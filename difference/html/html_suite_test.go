@@ -0,0 +1,13 @@
+package html_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiferenciaHtml(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diferencia HTML Suite")
+}
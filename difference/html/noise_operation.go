@@ -0,0 +1,90 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/net/html"
+)
+
+// NoiseOperation struct
+type NoiseOperation struct {
+	// Rules of the nodes considered noise
+	Rules []string
+}
+
+// Initialize with some rules
+func (nd *NoiseOperation) Initialize(rules []string) {
+	nd.Rules = append(nd.Rules, rules...)
+}
+
+// ContainsNoise method
+func (nd NoiseOperation) ContainsNoise() bool {
+	return len(nd.Rules) > 0
+}
+
+// Detect Noise between documents. Only texts and attributes with different values are considered noise.
+func (nd *NoiseOperation) Detect(primary, secondary []byte) error {
+
+	rules, err := compileRules(nd.Rules)
+	if err != nil {
+		return err
+	}
+
+	primaryDocument, err := prepare(primary, rules)
+	if err != nil {
+		return err
+	}
+
+	secondaryDocument, err := prepare(secondary, rules)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range compareDocuments(primaryDocument, secondaryDocument, false) {
+		if d.structural {
+			return fmt.Errorf("Primary and Secondary payload contains other changes apart from replacing values %s", d.String())
+		}
+		nd.Rules = append(nd.Rules, d.path)
+	}
+
+	return nil
+}
+
+// Remove noise from primary and candidate documents
+func (nd *NoiseOperation) Remove(primary, candidate []byte) ([]byte, []byte, error) {
+
+	rules, err := compileRules(nd.Rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	primaryWithoutNoise, err := render(primary, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateWithoutNoise, err := render(candidate, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return primaryWithoutNoise, candidateWithoutNoise, nil
+}
+
+func render(document []byte, rules []rule) ([]byte, error) {
+
+	parsed, err := html.Parse(bytes.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	removeNodes(parsed, rules)
+
+	var buffer bytes.Buffer
+	if err := html.Render(&buffer, parsed); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
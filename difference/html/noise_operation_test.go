@@ -0,0 +1,77 @@
+package html_test
+
+import (
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/html"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Html Noise Operation", func() {
+
+	Describe("Detect noise", func() {
+		Context("With documents with different values", func() {
+			It("should detect texts and attributes as noise", func() {
+				primary := loadFromFile("test_fixtures/page-a.html")
+				secondary := loadFromFile("test_fixtures/page-a-secondary.html")
+
+				noiseOperation := html.NoiseOperation{}
+				err := noiseOperation.Detect(primary, secondary)
+
+				Expect(err).Should(Succeed())
+				Expect(noiseOperation.Rules).Should(Equal([]string{
+					"html > head > script@nonce",
+					"html > body > div > form > input:nth-of-type(1)@value",
+					"html > body > div > p::text",
+				}))
+			})
+
+			It("should fail when documents have different structure", func() {
+				primary := loadFromFile("test_fixtures/page-a.html")
+				secondary := loadFromFile("test_fixtures/page-a-superset.html")
+
+				noiseOperation := html.NoiseOperation{}
+				err := noiseOperation.Detect(primary, secondary)
+
+				Expect(err).ShouldNot(Succeed())
+			})
+		})
+	})
+
+	Describe("Remove noise", func() {
+		Context("With detected noise", func() {
+			It("should return equal documents", func() {
+				primary := loadFromFile("test_fixtures/page-a.html")
+				secondary := loadFromFile("test_fixtures/page-a-secondary.html")
+				candidate := loadFromFile("test_fixtures/page-a-candidate.html")
+
+				noiseOperation := html.NoiseOperation{}
+				noiseOperation.Detect(primary, secondary)
+				primaryWithoutNoise, candidateWithoutNoise, err := noiseOperation.Remove(primary, candidate)
+
+				Expect(err).Should(Succeed())
+				result, output := html.CompareDocuments(candidateWithoutNoise, primaryWithoutNoise, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return regressions that are not noise", func() {
+				primary := loadFromFile("test_fixtures/page-a.html")
+				secondary := loadFromFile("test_fixtures/page-a-secondary.html")
+				candidate := loadFromFile("test_fixtures/page-a-changed.html")
+
+				noiseOperation := html.NoiseOperation{}
+				noiseOperation.Detect(primary, secondary)
+				primaryWithoutNoise, candidateWithoutNoise, err := noiseOperation.Remove(primary, candidate)
+
+				Expect(err).Should(Succeed())
+				result, output := html.CompareDocuments(candidateWithoutNoise, primaryWithoutNoise, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(Equal(`"html > body > div > ul > li:nth-of-type(2)::text": "Pencil" => "Pen"`))
+			})
+		})
+	})
+
+})
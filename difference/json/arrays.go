@@ -0,0 +1,280 @@
+package json
+
+import (
+	jsonenc "encoding/json"
+	"sort"
+	"strconv"
+)
+
+// sortUnorderedArrays sorts the elements of unordered arrays by their canonical representation.
+// Children are sorted before parents so nested unordered arrays have a canonical representation too.
+// If order is not nil, the original index of each sorted element is stored in it under the pointer of the array in the original document.
+func sortUnorderedArrays(document interface{}, path []string, unorderedArrays []pointerPattern, order map[string][]int) interface{} {
+
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			value[key] = sortUnorderedArrays(child, appendToken(path, key), unorderedArrays, order)
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = sortUnorderedArrays(child, appendToken(path, strconv.Itoa(i)), unorderedArrays, order)
+		}
+
+		if matchesAny(unorderedArrays, path) {
+			elements := make(byCanonical, len(value))
+			for i, child := range value {
+				elements[i] = canonicalElement{index: i, canonical: canonical(child), value: child}
+			}
+			sort.Stable(elements)
+
+			indexes := make([]int, len(elements))
+			for i, element := range elements {
+				value[i] = element.value
+				indexes[i] = element.index
+			}
+
+			if order != nil {
+				order[toPointer(path)] = indexes
+			}
+		}
+	}
+
+	return document
+}
+
+// alignUnorderedArrays reorders the elements of unordered arrays of other document so equal elements are placed at the same index as in primary.
+// Elements not present in primary fill the positions of primary elements not present in other document, and the rest are appended at the end.
+func alignUnorderedArrays(primary, other interface{}, path []string, unorderedArrays []pointerPattern) interface{} {
+
+	switch otherValue := other.(type) {
+	case map[string]interface{}:
+		primaryValue, ok := primary.(map[string]interface{})
+		if !ok {
+			return other
+		}
+		for key, child := range otherValue {
+			if primaryChild, ok := primaryValue[key]; ok {
+				otherValue[key] = alignUnorderedArrays(primaryChild, child, appendToken(path, key), unorderedArrays)
+			}
+		}
+	case []interface{}:
+		primaryValue, ok := primary.([]interface{})
+		if !ok {
+			return other
+		}
+
+		if matchesAny(unorderedArrays, path) {
+			otherValue = alignElements(primaryValue, otherValue, canonical)
+		}
+
+		for i := range otherValue {
+			if i < len(primaryValue) {
+				otherValue[i] = alignUnorderedArrays(primaryValue[i], otherValue[i], appendToken(path, strconv.Itoa(i)), unorderedArrays)
+			}
+		}
+
+		return otherValue
+	}
+
+	return other
+}
+
+// alignElements places the elements of other at the index of the primary element with the same identity
+func alignElements(primary, other []interface{}, identity func(interface{}) string) []interface{} {
+
+	otherCanonicals := make([]string, len(other))
+	for j, otherElement := range other {
+		otherCanonicals[j] = identity(otherElement)
+	}
+
+	used := make([]bool, len(other))
+	aligned := make([]interface{}, len(primary))
+	matched := make([]bool, len(primary))
+
+	for i, primaryElement := range primary {
+		primaryCanonical := identity(primaryElement)
+		for j, otherElement := range other {
+			if !used[j] && otherCanonicals[j] == primaryCanonical {
+				aligned[i] = otherElement
+				matched[i] = true
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	var leftovers []interface{}
+	for j, otherElement := range other {
+		if !used[j] {
+			leftovers = append(leftovers, otherElement)
+		}
+	}
+
+	result := make([]interface{}, 0, len(other))
+	for i := range primary {
+		if matched[i] {
+			result = append(result, aligned[i])
+		} else if len(leftovers) > 0 {
+			result = append(result, leftovers[0])
+			leftovers = leftovers[1:]
+		}
+	}
+
+	return append(result, leftovers...)
+}
+
+type canonicalElement struct {
+	index     int
+	canonical string
+	value     interface{}
+}
+
+type byCanonical []canonicalElement
+
+func (elements byCanonical) Len() int      { return len(elements) }
+func (elements byCanonical) Swap(i, j int) { elements[i], elements[j] = elements[j], elements[i] }
+func (elements byCanonical) Less(i, j int) bool {
+	return elements[i].canonical < elements[j].canonical
+}
+
+func canonical(value interface{}) string {
+	// Maps are marshalled with sorted keys so the representation does not depend on keys order
+	content, _ := jsonenc.Marshal(value)
+	return string(content)
+}
+
+type keyedArray struct {
+	pattern pointerPattern
+	key     string
+}
+
+func compileKeyedArrays(arrayKeys map[string]string) []keyedArray {
+	// Pointers are sorted so in case of more than one pointer matching the same array, the chosen key is always the same
+	var pointers []string
+	for pointer := range arrayKeys {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+
+	var keyedArrays []keyedArray
+	for _, pointer := range pointers {
+		keyedArrays = append(keyedArrays, keyedArray{pattern: compilePointerPattern(pointer), key: arrayKeys[pointer]})
+	}
+	return keyedArrays
+}
+
+func findArrayKey(keyedArrays []keyedArray, path []string) (string, bool) {
+	for _, keyedArray := range keyedArrays {
+		if keyedArray.pattern.matches(path) {
+			return keyedArray.key, true
+		}
+	}
+	return "", false
+}
+
+// indexKeyedArrays transforms keyed arrays to objects where each element is stored under its key value (key=value),
+// so elements are matched by identity and not by position.
+// If any element is not an object, has no key or its key is duplicated, the array is not transformed.
+func indexKeyedArrays(document interface{}, path []string, keyedArrays []keyedArray) interface{} {
+
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			value[key] = indexKeyedArrays(child, appendToken(path, key), keyedArrays)
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = indexKeyedArrays(child, appendToken(path, strconv.Itoa(i)), keyedArrays)
+		}
+
+		if key, ok := findArrayKey(keyedArrays, path); ok {
+			if index, ok := indexByKey(value, key); ok {
+				return index
+			}
+		}
+	}
+
+	return document
+}
+
+// alignKeyedArrays reorders the elements of keyed arrays of other document so elements are placed at the same index as the primary element
+// with the same key, keeping the index of primary elements instead of transforming arrays to objects.
+func alignKeyedArrays(primary, other interface{}, path []string, keyedArrays []keyedArray) interface{} {
+
+	switch otherValue := other.(type) {
+	case map[string]interface{}:
+		primaryValue, ok := primary.(map[string]interface{})
+		if !ok {
+			return other
+		}
+		for key, child := range otherValue {
+			if primaryChild, ok := primaryValue[key]; ok {
+				otherValue[key] = alignKeyedArrays(primaryChild, child, appendToken(path, key), keyedArrays)
+			}
+		}
+	case []interface{}:
+		primaryValue, ok := primary.([]interface{})
+		if !ok {
+			return other
+		}
+
+		if key, ok := findArrayKey(keyedArrays, path); ok {
+			_, primaryIndexed := indexByKey(primaryValue, key)
+			_, otherIndexed := indexByKey(otherValue, key)
+			if primaryIndexed && otherIndexed {
+				otherValue = alignElements(primaryValue, otherValue, func(element interface{}) string {
+					keyValue, _ := scalarToString(element.(map[string]interface{})[key])
+					return keyValue
+				})
+			}
+		}
+
+		for i := range otherValue {
+			if i < len(primaryValue) {
+				otherValue[i] = alignKeyedArrays(primaryValue[i], otherValue[i], appendToken(path, strconv.Itoa(i)), keyedArrays)
+			}
+		}
+
+		return otherValue
+	}
+
+	return other
+}
+
+func indexByKey(elements []interface{}, key string) (map[string]interface{}, bool) {
+
+	index := make(map[string]interface{}, len(elements))
+
+	for _, element := range elements {
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		keyValue, ok := scalarToString(object[key])
+		if !ok {
+			return nil, false
+		}
+
+		elementKey := key + "=" + keyValue
+		if _, duplicated := index[elementKey]; duplicated {
+			return nil, false
+		}
+		index[elementKey] = element
+	}
+
+	return index, true
+}
+
+func scalarToString(value interface{}) (string, bool) {
+	switch scalar := value.(type) {
+	case string:
+		return scalar, true
+	case jsonenc.Number:
+		return scalar.String(), true
+	case bool:
+		return strconv.FormatBool(scalar), true
+	}
+	return "", false
+}
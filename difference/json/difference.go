@@ -1,14 +1,48 @@
 package json
 
 import (
+	"strings"
+
 	"github.com/lordofthejars/jsondiff"
 )
 
 // CompareDocuments comparing two JSON documents and returns true or false according to configured difference
 func CompareDocuments(candidate, original []byte, difference string) (bool, string) {
-	options := defaultJsonOptions()
+	return CompareDocumentsWithOptions(candidate, original, difference, Options{})
+}
+
+// CompareDocumentsWithOptions comparing two JSON documents and returns true or false according to configured difference and options
+func CompareDocumentsWithOptions(candidate, original []byte, difference string, options Options) (bool, string) {
+
+	candidate, mismatches, err := MatchValues(candidate, original, options)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	original, candidate, err = options.prepare(original, candidate)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	result, output := compareDocuments(candidate, original, difference)
+
+	// Values not matching their matcher are a difference even if they are equal to primary values
+	if len(mismatches) > 0 {
+		return false, strings.TrimSuffix(strings.Join(mismatches, "\n")+"\n"+output, "\n")
+	}
+
+	return result, output
+}
+
+func compareDocuments(candidate, original []byte, difference string) (bool, string) {
+
+	if difference == "Schema" {
+		return compareSchemas(candidate, original)
+	}
+
+	outputOptions := defaultJsonOptions()
 
-	result, output := jsondiff.Compare(candidate, original, &options)
+	result, output := jsondiff.Compare(candidate, original, &outputOptions)
 
 	finalResult := false
 	finalOutput := ""
@@ -3,6 +3,7 @@ package json_test
 import (
 	"fmt"
 	"io/ioutil"
+	"regexp"
 
 	"github.com/lordofthejars/diferencia/core"
 
@@ -119,6 +120,209 @@ var _ = Describe("Json Difference", func() {
 		})
 	})
 
+	Describe("Compare Two Json documents by schema", func() {
+		Context("With schema mode", func() {
+			It("should return that are equal when only values change", func() {
+				documentA := loadFromFile("test_fixtures/document-a.json")
+				documentB := loadFromFile("test_fixtures/document-a-other-values.json")
+
+				result, output := json.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when a key is added", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-update.json")
+
+				result, output := json.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(false))
+				Expect(output).To(Equal(`"/e": undefined => number`))
+			})
+
+			It("should return that are different when type changes", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-update-type.json")
+
+				result, output := json.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(false))
+				Expect(output).To(Equal(`"/b/c": number => string`))
+			})
+
+			It("should return that are different when nesting changes", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-simple.json")
+
+				result, output := json.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(false))
+				Expect(output).To(Equal(`"/b": object => number`))
+			})
+
+			It("should return that are equal when values are structurally the same", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-other-values.json")
+
+				result, output := json.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Compare Two Json documents with unordered arrays", func() {
+		unorderedArrays := json.Options{UnorderedArrays: []string{"/items", "/users/*/roles"}}
+
+		Context("With strict mode", func() {
+			It("should return that are equal when only order changes", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), unorderedArrays)
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when arrays are not configured as unordered", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), json.Options{UnorderedArrays: []string{"/items"}})
+				Expect(result).To(Equal(false))
+				Expect(len(output)).Should(BeNumerically(">", 0))
+			})
+
+			It("should return that are different when an element is added", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered-superset.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), unorderedArrays)
+				Expect(result).To(Equal(false))
+				Expect(len(output)).Should(BeNumerically(">", 0))
+			})
+		})
+
+		Context("With subset mode", func() {
+			It("should return that are equal when an element is added", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered-superset.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Subset.String(), unorderedArrays)
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when an element is removed", func() {
+				documentA := loadFromFile("test_fixtures/document-d-reordered-superset.json")
+				documentB := loadFromFile("test_fixtures/document-d.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Subset.String(), unorderedArrays)
+				Expect(result).To(Equal(false))
+				Expect(len(output)).Should(BeNumerically(">", 0))
+			})
+		})
+	})
+
+	Describe("Compare Two Json documents with masked strings", func() {
+		date := regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+		masked := json.Options{Mask: func(value string) string {
+			return date.ReplaceAllString(value, "DATE")
+		}}
+
+		It("should return that are equal when only masked texts change", func() {
+			documentA := []byte(`{"id": 1, "created": "2018-10-01", "history": ["created at 2018-10-01"]}`)
+			documentB := []byte(`{"id": 1, "created": "2019-01-31", "history": ["created at 2019-01-31"]}`)
+
+			result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), masked)
+			Expect(result).To(Equal(true))
+			Expect(len(output)).To(Equal(0))
+		})
+
+		It("should return that are different when not masked texts change", func() {
+			documentA := []byte(`{"id": 1, "created": "2018-10-01", "history": ["created at 2018-10-01"]}`)
+			documentB := []byte(`{"id": 1, "created": "2019-01-31", "history": ["updated at 2019-01-31"]}`)
+
+			result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), masked)
+			Expect(result).To(Equal(false))
+			Expect(output).Should(ContainSubstring(`"updated at DATE"`))
+			Expect(output).ShouldNot(ContainSubstring("2019"))
+		})
+	})
+
+	Describe("Compare Two Json documents with keyed arrays", func() {
+		keyedArrays := json.Options{ArrayKeys: map[string]string{"/orders": "sku"}}
+
+		Context("With strict mode", func() {
+			It("should return only the inserted element when an element is added in the middle", func() {
+				documentA := loadFromFile("test_fixtures/document-e.json")
+				documentB := loadFromFile("test_fixtures/document-e-inserted.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), keyedArrays)
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring(`"sku=Z-9"`))
+				Expect(output).ShouldNot(ContainSubstring(`"quantity": 1 =>`))
+			})
+
+			It("should name the changed element by its key value", func() {
+				documentA := loadFromFile("test_fixtures/document-e.json")
+				documentB := loadFromFile("test_fixtures/document-e-changed.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), keyedArrays)
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring(`"sku=B-2"`))
+			})
+		})
+
+		Context("With subset mode", func() {
+			It("should return that are equal when an element is added in the middle", func() {
+				documentA := loadFromFile("test_fixtures/document-e.json")
+				documentB := loadFromFile("test_fixtures/document-e-inserted.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Subset.String(), keyedArrays)
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Compare Two Json documents with numeric tolerances", func() {
+		tolerances := json.Options{
+			Tolerances: map[string]json.Tolerance{
+				"/price": json.Tolerance{Absolute: 0.001},
+				"/score": json.Tolerance{Relative: 0.01},
+			},
+			DefaultTolerance: json.Tolerance{Absolute: 0.000001},
+		}
+
+		Context("With strict mode", func() {
+			It("should return that are equal when numbers are within tolerance", func() {
+				documentA := loadFromFile("test_fixtures/document-f.json")
+				documentB := loadFromFile("test_fixtures/document-f-rounding.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), tolerances)
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when tolerances are not set", func() {
+				documentA := loadFromFile("test_fixtures/document-f.json")
+				documentB := loadFromFile("test_fixtures/document-f-rounding.json")
+
+				result, _ := json.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+			})
+
+			It("should report both values when numbers are out of tolerance", func() {
+				documentA := loadFromFile("test_fixtures/document-f.json")
+				documentB := loadFromFile("test_fixtures/document-f-changed.json")
+
+				result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), tolerances)
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring("10.75"))
+				Expect(output).Should(ContainSubstring("10.25"))
+			})
+		})
+	})
+
 })
 
 func loadFromFile(filePath string) []byte {
@@ -0,0 +1,391 @@
+package json
+
+import (
+	jsonenc "encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const jsonPathRoot = "$"
+
+// jsonPath is a compiled JSONPath expression like $.orders[?(@.status=='PENDING')].eta.
+// Only a subset of JSONPath is supported: children (.name or ['name']), indexes ([0]), wildcards (.* or [*]),
+// recursive descent (..name) and filters with a single condition ([?(@.name)], [?(@.name=='value')] or [?(@.name!='value')]).
+// Any other construct, like unions, slices, negative indexes, escaped quotes or joined conditions, is rejected when the expression is compiled.
+type jsonPath []jsonPathSegment
+
+type jsonPathSegment struct {
+	recursive bool
+	wildcard  bool
+	name      string
+	// index of the selected element, or -1 if a name is selected
+	index  int
+	filter *jsonPathFilter
+}
+
+// jsonPathFilter selects the children that contain the value of the relative path, and if there is an operator,
+// whose value is equal (==) or different (!=) to the literal
+type jsonPathFilter struct {
+	path     []string
+	operator string
+	literal  interface{}
+}
+
+// IsJSONPath returns true if the path is a JSONPath expression instead of a JSON pointer
+func IsJSONPath(path string) bool {
+	return strings.HasPrefix(path, jsonPathRoot)
+}
+
+// ValidatePath checks that the path, which can be a JSON pointer or a JSONPath expression, is valid
+func ValidatePath(path string) error {
+	if !IsJSONPath(path) {
+		return nil
+	}
+
+	_, err := compileJSONPath(path)
+	return err
+}
+
+func compileJSONPath(expression string) (jsonPath, error) {
+	parser := jsonPathParser{text: expression, position: len(jsonPathRoot)}
+	var path jsonPath
+
+	for !parser.done() {
+		segment, err := parser.segment()
+		if err != nil {
+			return nil, fmt.Errorf("JSONPath %s is not valid. %s", expression, err.Error())
+		}
+		path = append(path, segment)
+	}
+
+	return path, nil
+}
+
+type jsonPathParser struct {
+	text     string
+	position int
+}
+
+func (parser *jsonPathParser) done() bool {
+	return parser.position >= len(parser.text)
+}
+
+func (parser *jsonPathParser) consume(token string) bool {
+	if strings.HasPrefix(parser.text[parser.position:], token) {
+		parser.position += len(token)
+		return true
+	}
+	return false
+}
+
+func (parser *jsonPathParser) skipSpaces() {
+	for !parser.done() && parser.text[parser.position] == ' ' {
+		parser.position++
+	}
+}
+
+func (parser *jsonPathParser) segment() (jsonPathSegment, error) {
+	segment := jsonPathSegment{index: -1}
+
+	switch {
+	case parser.consume(".."):
+		segment.recursive = true
+		if parser.consume("[") {
+			return parser.bracket(segment)
+		}
+		return parser.dotName(segment)
+	case parser.consume("."):
+		return parser.dotName(segment)
+	case parser.consume("["):
+		return parser.bracket(segment)
+	}
+
+	return segment, parser.unexpected()
+}
+
+func (parser *jsonPathParser) dotName(segment jsonPathSegment) (jsonPathSegment, error) {
+	if parser.consume("*") {
+		segment.wildcard = true
+		return segment, nil
+	}
+
+	segment.name = parser.name()
+	if len(segment.name) == 0 {
+		return segment, parser.unexpected()
+	}
+	return segment, nil
+}
+
+// name reads a name made of letters, digits, underscores and hyphens
+func (parser *jsonPathParser) name() string {
+	start := parser.position
+	for !parser.done() && isNameCharacter(parser.text[parser.position]) {
+		parser.position++
+	}
+	return parser.text[start:parser.position]
+}
+
+func isNameCharacter(c byte) bool {
+	return c == '_' || c == '-' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (parser *jsonPathParser) bracket(segment jsonPathSegment) (jsonPathSegment, error) {
+	var err error
+
+	switch {
+	case parser.consume("*"):
+		segment.wildcard = true
+	case parser.consume("?("):
+		segment.filter, err = parser.filter()
+	case parser.quoted():
+		segment.name, err = parser.quotedText()
+	default:
+		segment.index, err = parser.index()
+	}
+
+	if err != nil {
+		return segment, err
+	}
+
+	if !parser.consume("]") {
+		return segment, parser.unexpected()
+	}
+	return segment, nil
+}
+
+func (parser *jsonPathParser) quoted() bool {
+	return !parser.done() && (parser.text[parser.position] == '\'' || parser.text[parser.position] == '"')
+}
+
+// quotedText reads a text enclosed in single or double quotes, which cannot contain escaped characters
+func (parser *jsonPathParser) quotedText() (string, error) {
+	quote := parser.text[parser.position]
+	start := parser.position + 1
+
+	for i := start; i < len(parser.text); i++ {
+		switch parser.text[i] {
+		case '\\':
+			return "", fmt.Errorf("escaped characters in quoted text at position %d are not supported", i)
+		case quote:
+			parser.position = i + 1
+			return parser.text[start:i], nil
+		}
+	}
+
+	return "", fmt.Errorf("quoted text at position %d is not closed", parser.position)
+}
+
+func (parser *jsonPathParser) index() (int, error) {
+	start := parser.position
+	for !parser.done() && parser.text[parser.position] >= '0' && parser.text[parser.position] <= '9' {
+		parser.position++
+	}
+
+	if start == parser.position {
+		return -1, parser.unexpected()
+	}
+	return strconv.Atoi(parser.text[start:parser.position])
+}
+
+func (parser *jsonPathParser) filter() (*jsonPathFilter, error) {
+	filter := &jsonPathFilter{}
+
+	parser.skipSpaces()
+	if !parser.consume("@") {
+		return nil, parser.unexpected()
+	}
+
+	for parser.consume(".") {
+		name := parser.name()
+		if len(name) == 0 {
+			return nil, parser.unexpected()
+		}
+		filter.path = append(filter.path, name)
+	}
+
+	if len(filter.path) == 0 {
+		return nil, parser.unexpected()
+	}
+
+	parser.skipSpaces()
+	for _, operator := range []string{"==", "!="} {
+		if parser.consume(operator) {
+			filter.operator = operator
+		}
+	}
+
+	if len(filter.operator) > 0 {
+		parser.skipSpaces()
+		literal, err := parser.literal()
+		if err != nil {
+			return nil, err
+		}
+		filter.literal = literal
+		parser.skipSpaces()
+	}
+
+	if !parser.consume(")") {
+		return nil, parser.unexpected()
+	}
+	return filter, nil
+}
+
+func (parser *jsonPathParser) literal() (interface{}, error) {
+	if parser.quoted() {
+		return parser.quotedText()
+	}
+
+	for _, keyword := range []struct {
+		text  string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if parser.consume(keyword.text) {
+			return keyword.value, nil
+		}
+	}
+
+	start := parser.position
+	for !parser.done() && strings.IndexByte("+-.0123456789eE", parser.text[parser.position]) >= 0 {
+		parser.position++
+	}
+
+	number := parser.text[start:parser.position]
+	if _, err := strconv.ParseFloat(number, 64); err != nil {
+		parser.position = start
+		return nil, parser.unexpected()
+	}
+	return jsonenc.Number(number), nil
+}
+
+// unexpected returns an error for the text at the current position, naming the construct when it is a known unsupported one
+func (parser *jsonPathParser) unexpected() error {
+	if parser.done() {
+		return fmt.Errorf("unexpected end of expression")
+	}
+
+	rest := parser.text[parser.position:]
+	unsupported := map[string]string{
+		",":  "unions",
+		":":  "array slices",
+		"-":  "negative indexes",
+		"&&": "conditions joined with &&",
+		"||": "conditions joined with ||",
+		"<":  "comparisons other than == and !=",
+		">":  "comparisons other than == and !=",
+		"(":  "script expressions",
+		"$":  "absolute paths in filters",
+		"[":  "brackets in filters",
+	}
+
+	for token, construct := range unsupported {
+		if strings.HasPrefix(rest, token) {
+			return fmt.Errorf("%s at position %d are not supported", construct, parser.position)
+		}
+	}
+
+	return fmt.Errorf("unexpected %q at position %d", rest, parser.position)
+}
+
+// pointers returns the JSON pointers of all values of the document selected by the expression
+func (path jsonPath) pointers(document interface{}) []string {
+	nodes := []jsonPathNode{{value: document}}
+
+	for _, segment := range path {
+		var selected []jsonPathNode
+		for _, node := range nodes {
+			if segment.recursive {
+				for _, descendant := range descendants(node) {
+					selected = append(selected, segment.selectChildren(descendant)...)
+				}
+			} else {
+				selected = append(selected, segment.selectChildren(node)...)
+			}
+		}
+		nodes = selected
+	}
+
+	var pointers []string
+	found := make(map[string]bool)
+	for _, node := range nodes {
+		pointer := toPointer(node.path)
+		if !found[pointer] {
+			found[pointer] = true
+			pointers = append(pointers, pointer)
+		}
+	}
+
+	return pointers
+}
+
+type jsonPathNode struct {
+	path  []string
+	value interface{}
+}
+
+// descendants returns the node and all its descendants
+func descendants(node jsonPathNode) []jsonPathNode {
+	nodes := []jsonPathNode{node}
+	forEachChild(node.value, func(key string, child interface{}) {
+		nodes = append(nodes, descendants(jsonPathNode{path: appendToken(node.path, key), value: child})...)
+	})
+	return nodes
+}
+
+func (segment jsonPathSegment) selectChildren(node jsonPathNode) []jsonPathNode {
+	var selected []jsonPathNode
+
+	switch value := node.value.(type) {
+	case map[string]interface{}:
+		if child, ok := value[segment.name]; ok && len(segment.name) > 0 {
+			return []jsonPathNode{{path: appendToken(node.path, segment.name), value: child}}
+		}
+	case []interface{}:
+		if segment.index >= 0 && segment.index < len(value) {
+			return []jsonPathNode{{path: appendToken(node.path, strconv.Itoa(segment.index)), value: value[segment.index]}}
+		}
+	}
+
+	if segment.wildcard || segment.filter != nil {
+		forEachChild(node.value, func(key string, child interface{}) {
+			if segment.wildcard || segment.filter.matches(child) {
+				selected = append(selected, jsonPathNode{path: appendToken(node.path, key), value: child})
+			}
+		})
+	}
+
+	return selected
+}
+
+func (filter *jsonPathFilter) matches(value interface{}) bool {
+	for _, key := range filter.path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	switch filter.operator {
+	case "==":
+		return equalValues(value, filter.literal)
+	case "!=":
+		return !equalValues(value, filter.literal)
+	}
+	return true
+}
+
+func equalValues(left, right interface{}) bool {
+	leftNumber, leftOk := left.(jsonenc.Number)
+	rightNumber, rightOk := right.(jsonenc.Number)
+	if leftOk && rightOk {
+		leftValue, leftErr := leftNumber.Float64()
+		rightValue, rightErr := rightNumber.Float64()
+		if leftErr == nil && rightErr == nil {
+			return leftValue == rightValue
+		}
+	}
+	return canonical(left) == canonical(right)
+}
@@ -0,0 +1,150 @@
+package json_test
+
+import (
+	"fmt"
+
+	"github.com/lordofthejars/diferencia/difference/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONPath", func() {
+
+	removeNoise := func(paths ...string) (bool, string) {
+		documentA := loadFromFile("test_fixtures/document-j.json")
+		documentB := loadFromFile("test_fixtures/document-j-changed.json")
+
+		noiseOperation := json.NoiseOperation{}
+		noiseOperation.Initialize(paths)
+
+		primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+		if err != nil {
+			Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+		}
+
+		return json.CompareDocuments(candidate, primary, "Strict")
+	}
+
+	Describe("Validating expressions", func() {
+		accepted := []string{
+			"/orders/0/eta",
+			"$",
+			"$.orders",
+			"$['orders']",
+			"$[\"orders\"]",
+			"$.orders[0]",
+			"$.orders[*].eta",
+			"$.orders.*",
+			"$..eta",
+			"$..['eta']",
+			"$..*",
+			"$.orders[?(@.eta)]",
+			"$.orders[?(@.customer.name)].eta",
+			"$.orders[?(@.status=='PENDING')].eta",
+			"$.orders[?(@.status != \"SHIPPED\")].eta",
+			"$.orders[?(@.total == 12.5)].eta",
+			"$.orders[?(@.paid == true)].eta",
+			"$.orders[?(@.eta == null)]",
+			"$.orders[?(@.status=='PENDING, LATE')].eta",
+		}
+
+		for _, expression := range accepted {
+			expression := expression
+			It(fmt.Sprintf("should accept %s", expression), func() {
+				Expect(json.ValidatePath(expression)).Should(Succeed())
+			})
+		}
+
+		rejected := []struct{ expression, reason string }{
+			{"$['orders'][0,1]", "unions"},
+			{"$['orders','customers']", "unions"},
+			{"$.orders[0:2]", "array slices"},
+			{"$.orders[-1]", "negative indexes"},
+			{"$['a\\'b']", "escaped characters"},
+			{"$.orders[?(@.status == 'PENDING' && @.total >= 10)]", "conditions joined with &&"},
+			{"$.orders[?(@.status == 'PENDING' || @.total >= 10)]", "conditions joined with ||"},
+			{"$.orders[?(@.total > 50)]", "comparisons other than == and !="},
+			{"$.orders[(@.length-1)]", "script expressions"},
+			{"$.orders[?(@.status == $.status)]", "absolute paths in filters"},
+			{"$.orders[?(@['status'])]", "brackets in filters"},
+			{"$.orders[?(@.status == 'PENDING'].eta", "unexpected"},
+			{"$.orders[?(PENDING)]", "unexpected"},
+			{"$.orders[first]", "unexpected"},
+			{"$.orders.", "unexpected end"},
+			{"$.orders['eta", "not closed"},
+			{"$orders", "unexpected"},
+		}
+
+		for _, rejection := range rejected {
+			rejection := rejection
+			It(fmt.Sprintf("should reject %s", rejection.expression), func() {
+				err := json.ValidatePath(rejection.expression)
+
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(ContainSubstring(rejection.reason))
+			})
+		}
+	})
+
+	Describe("Removing Noise from Documents", func() {
+		It("should ignore values selected by a filter", func() {
+			result, diff := removeNoise("$.orders[?(@.status=='PENDING')].eta")
+
+			Expect(result).Should(Equal(false))
+			Expect(diff).Should(ContainSubstring("09:15:00"))
+			Expect(diff).ShouldNot(ContainSubstring("11:30:00"))
+			Expect(diff).ShouldNot(ContainSubstring("07:00:00"))
+		})
+
+		It("should ignore values selected by recursive descent", func() {
+			result, _ := removeNoise("$..eta")
+
+			Expect(result).Should(Equal(true))
+		})
+
+		It("should ignore values selected by filters on numbers and indexes", func() {
+			result, diff := removeNoise("$.orders[?(@.total == 99.90)].eta", "$.orders[0]['eta']")
+
+			Expect(result).Should(Equal(false))
+			Expect(diff).Should(ContainSubstring("09:15:00"))
+			Expect(diff).ShouldNot(ContainSubstring("11:30:00"))
+			Expect(diff).ShouldNot(ContainSubstring("07:00:00"))
+		})
+
+		It("should skip expressions without values in documents", func() {
+			result, _ := removeNoise("$.orders[?(@.status=='CANCELLED')].eta", "$.customers[*].name", "$..eta")
+
+			Expect(result).Should(Equal(true))
+		})
+
+		It("should fail if expression is not valid", func() {
+			documentA := loadFromFile("test_fixtures/document-j.json")
+			documentB := loadFromFile("test_fixtures/document-j-changed.json")
+
+			noiseOperation := json.NoiseOperation{}
+			noiseOperation.Initialize([]string{"$.orders[?(@.status)"})
+
+			_, _, err := noiseOperation.Remove(documentA, documentB)
+
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Compare Two Json documents with value matchers", func() {
+		It("should validate values selected by a filter", func() {
+			documentA := loadFromFile("test_fixtures/document-j.json")
+			documentB := loadFromFile("test_fixtures/document-j-changed.json")
+
+			iso8601, _ := json.ParseValueMatcher("iso8601")
+			options := json.Options{ValueMatchers: map[string]json.ValueMatcher{"$.orders[?(@.status=='PENDING')].eta": iso8601}}
+
+			result, diff := json.CompareDocumentsWithOptions(documentB, documentA, "Strict", options)
+
+			Expect(result).Should(Equal(false))
+			Expect(diff).Should(ContainSubstring("09:15:00"))
+			Expect(diff).ShouldNot(ContainSubstring("11:30:00"))
+		})
+	})
+})
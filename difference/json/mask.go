@@ -0,0 +1,19 @@
+package json
+
+// maskStrings replaces every string value of the document with its masked value. Keys of objects are not masked.
+func maskStrings(document interface{}, mask func(string) string) interface{} {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, element := range value {
+			value[key] = maskStrings(element, mask)
+		}
+	case []interface{}:
+		for i, element := range value {
+			value[i] = maskStrings(element, mask)
+		}
+	case string:
+		return mask(value)
+	}
+
+	return document
+}
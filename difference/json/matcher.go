@@ -0,0 +1,256 @@
+package json
+
+import (
+	jsonenc "encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const regexMatcherPrefix = "regex:"
+
+var (
+	uuidPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	iso8601Pattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}([T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}(:?\d{2})?)?)?$`)
+)
+
+// ValueMatcher validates the shape of a candidate value instead of comparing it with the primary value
+type ValueMatcher struct {
+	Name  string
+	match func(value interface{}) bool
+}
+
+// ParseValueMatcher from its name. Supported matchers are uuid, iso8601, integer, nonNegativeInteger, number, string, boolean, notNull, notEmpty
+// and regex:expression to match string values with a regular expression.
+func ParseValueMatcher(name string) (ValueMatcher, error) {
+
+	var match func(value interface{}) bool
+
+	switch name {
+	case "uuid":
+		match = stringMatching(uuidPattern)
+	case "iso8601":
+		match = stringMatching(iso8601Pattern)
+	case "integer":
+		match = func(value interface{}) bool {
+			_, ok := integer(value)
+			return ok
+		}
+	case "nonNegativeInteger":
+		match = func(value interface{}) bool {
+			number, ok := integer(value)
+			return ok && number >= 0
+		}
+	case "number":
+		match = func(value interface{}) bool {
+			_, ok := value.(jsonenc.Number)
+			return ok
+		}
+	case "string":
+		match = func(value interface{}) bool {
+			_, ok := value.(string)
+			return ok
+		}
+	case "boolean":
+		match = func(value interface{}) bool {
+			_, ok := value.(bool)
+			return ok
+		}
+	case "notNull":
+		match = func(value interface{}) bool {
+			return value != nil
+		}
+	case "notEmpty":
+		match = func(value interface{}) bool {
+			switch v := value.(type) {
+			case string:
+				return len(v) > 0
+			case []interface{}:
+				return len(v) > 0
+			case map[string]interface{}:
+				return len(v) > 0
+			}
+			return value != nil
+		}
+	default:
+		if !strings.HasPrefix(name, regexMatcherPrefix) {
+			return ValueMatcher{}, fmt.Errorf("Cannot find %s value matcher", name)
+		}
+
+		pattern, err := regexp.Compile(strings.TrimPrefix(name, regexMatcherPrefix))
+		if err != nil {
+			return ValueMatcher{}, fmt.Errorf("Value matcher %s is not a valid regular expression. %s", name, err.Error())
+		}
+		match = stringMatching(pattern)
+	}
+
+	return ValueMatcher{Name: name, match: match}, nil
+}
+
+// Matches returns true if value has the shape required by the matcher
+func (matcher ValueMatcher) Matches(value interface{}) bool {
+	return matcher.match(value)
+}
+
+func stringMatching(pattern *regexp.Regexp) func(value interface{}) bool {
+	return func(value interface{}) bool {
+		text, ok := value.(string)
+		return ok && pattern.MatchString(text)
+	}
+}
+
+func integer(value interface{}) (int64, bool) {
+	number, ok := value.(jsonenc.Number)
+	if !ok {
+		return 0, false
+	}
+
+	integer, err := strconv.ParseInt(number.String(), 10, 64)
+	return integer, err == nil
+}
+
+type matcherRule struct {
+	pattern pointerPattern
+	// pointers selected by a JSONPath expression in the document being validated, or nil if the rule is a JSON pointer
+	pointers map[string]bool
+	matcher  ValueMatcher
+}
+
+// compileMatcherRules for the document being validated, since rules defined with JSONPath expressions select values depending on the document
+func compileMatcherRules(matchers map[string]ValueMatcher, document interface{}) ([]matcherRule, error) {
+	// Pointers are sorted so in case of more than one pointer matching the same value, the chosen matcher is always the same
+	var pointers []string
+	for pointer := range matchers {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+
+	var rules []matcherRule
+	for _, pointer := range pointers {
+		rule := matcherRule{matcher: matchers[pointer]}
+
+		if IsJSONPath(pointer) {
+			path, err := compileJSONPath(pointer)
+			if err != nil {
+				return nil, err
+			}
+
+			rule.pointers = make(map[string]bool)
+			for _, selected := range path.pointers(document) {
+				rule.pointers[selected] = true
+			}
+		} else {
+			rule.pattern = compilePointerPattern(pointer)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (rule matcherRule) matches(path []string) bool {
+	if rule.pointers != nil {
+		return rule.pointers[toPointer(path)]
+	}
+	return rule.pattern.matches(path)
+}
+
+func findMatcher(rules []matcherRule, path []string) (ValueMatcher, bool) {
+	for _, rule := range rules {
+		if rule.matches(path) {
+			return rule.matcher, true
+		}
+	}
+	return ValueMatcher{}, false
+}
+
+// MatchValues validates candidate values against the value matchers of options. Matching values are replaced by the primary ones so they are
+// considered equal, and it returns the modified candidate with the values not matching their matcher.
+// Matchers must be applied once to the original candidate, before noise is removed or documents are prepared, so values are validated as received.
+// Elements of keyed arrays are paired with the primary element with the same key.
+func MatchValues(candidate, primary []byte, options Options) ([]byte, []string, error) {
+
+	if len(options.ValueMatchers) == 0 {
+		return candidate, nil, nil
+	}
+
+	candidateDocument, err := decode(candidate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	primaryDocument, err := decode(primary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules, err := compileMatcherRules(options.ValueMatchers, candidateDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mismatches []string
+	candidateDocument = applyValueMatchers(primaryDocument, true, candidateDocument, nil, rules, compileKeyedArrays(options.ArrayKeys), &mismatches)
+
+	matched, err := jsonenc.Marshal(candidateDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return matched, mismatches, nil
+}
+
+// applyValueMatchers replaces values of other document by the primary ones when they match their matcher, so they are considered equal.
+// Values not matching are not modified so both values are reported as difference, and the failed matcher is added to mismatches.
+func applyValueMatchers(primary interface{}, inPrimary bool, other interface{}, path []string, rules []matcherRule, keyedArrays []keyedArray, mismatches *[]string) interface{} {
+
+	if matcher, ok := findMatcher(rules, path); ok {
+		if !matcher.Matches(other) {
+			*mismatches = append(*mismatches, fmt.Sprintf("%q: %s does not match %s", toPointer(path), canonical(other), matcher.Name))
+			return other
+		}
+
+		if inPrimary {
+			return primary
+		}
+		return other
+	}
+
+	switch otherValue := other.(type) {
+	case map[string]interface{}:
+		primaryValue, _ := primary.(map[string]interface{})
+		for key, child := range otherValue {
+			primaryChild, ok := primaryValue[key]
+			otherValue[key] = applyValueMatchers(primaryChild, ok, child, appendToken(path, key), rules, keyedArrays, mismatches)
+		}
+	case []interface{}:
+		primaryValue, _ := primary.([]interface{})
+		counterpart := func(i int) (interface{}, bool) {
+			if i < len(primaryValue) {
+				return primaryValue[i], true
+			}
+			return nil, false
+		}
+
+		if key, ok := findArrayKey(keyedArrays, path); ok {
+			primaryIndex, primaryIndexed := indexByKey(primaryValue, key)
+			_, otherIndexed := indexByKey(otherValue, key)
+			if primaryIndexed && otherIndexed {
+				counterpart = func(i int) (interface{}, bool) {
+					keyValue, _ := scalarToString(otherValue[i].(map[string]interface{})[key])
+					primaryChild, ok := primaryIndex[key+"="+keyValue]
+					return primaryChild, ok
+				}
+			}
+		}
+
+		for i := range otherValue {
+			primaryChild, ok := counterpart(i)
+			otherValue[i] = applyValueMatchers(primaryChild, ok, otherValue[i], appendToken(path, strconv.Itoa(i)), rules, keyedArrays, mismatches)
+		}
+	}
+
+	return other
+}
@@ -0,0 +1,107 @@
+package json_test
+
+import (
+	"github.com/lordofthejars/diferencia/core"
+	"github.com/lordofthejars/diferencia/difference/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Value Matchers", func() {
+
+	matchers := func(definitions map[string]string) json.Options {
+		valueMatchers := make(map[string]json.ValueMatcher)
+		for pointer, name := range definitions {
+			matcher, err := json.ParseValueMatcher(name)
+			Expect(err).Should(Succeed())
+			valueMatchers[pointer] = matcher
+		}
+		return json.Options{ValueMatchers: valueMatchers}
+	}
+
+	Describe("Parsing value matchers", func() {
+		It("should match values with the required shape", func() {
+			uuid, _ := json.ParseValueMatcher("uuid")
+			iso8601, _ := json.ParseValueMatcher("iso8601")
+			nonNegativeInteger, _ := json.ParseValueMatcher("nonNegativeInteger")
+			regex, _ := json.ParseValueMatcher("regex:^[A-Z]{3}$")
+
+			Expect(uuid.Matches("0d1e2f3a-4b5c-6d7e-8f90-a1b2c3d4e5f6")).Should(BeTrue())
+			Expect(uuid.Matches(nil)).Should(BeFalse())
+			Expect(iso8601.Matches("2018-06-18T11:46:23+02:00")).Should(BeTrue())
+			Expect(iso8601.Matches("Mon, 18 Jun 2018 11:46:23 GMT")).Should(BeFalse())
+			Expect(regex.Matches("EUR")).Should(BeTrue())
+			Expect(regex.Matches("eur")).Should(BeFalse())
+			Expect(nonNegativeInteger.Name).Should(Equal("nonNegativeInteger"))
+		})
+
+		It("should fail if matcher does not exist", func() {
+			_, err := json.ParseValueMatcher("klingon")
+
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should fail if regular expression is not valid", func() {
+			_, err := json.ParseValueMatcher("regex:[a-")
+
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Compare Two Json documents with value matchers", func() {
+		options := matchers(map[string]string{
+			"/id":         "uuid",
+			"/createdAt":  "iso8601",
+			"/count":      "nonNegativeInteger",
+			"/items/*/id": "uuid",
+		})
+
+		It("should return that are equal when values match their matchers", func() {
+			documentA := loadFromFile("test_fixtures/document-g.json")
+			documentB := loadFromFile("test_fixtures/document-g-other-values.json")
+
+			result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), options)
+			Expect(result).To(Equal(true))
+			Expect(len(output)).To(Equal(0))
+
+			operations, err := json.DiffDocumentsWithOptions(documentB, documentA, core.Strict.String(), options)
+			Expect(err).Should(Succeed())
+			Expect(operations).Should(BeEmpty())
+		})
+
+		It("should return the failed matchers when values do not match", func() {
+			documentA := loadFromFile("test_fixtures/document-g.json")
+			documentB := loadFromFile("test_fixtures/document-g-invalid-values.json")
+
+			result, output := json.CompareDocumentsWithOptions(documentB, documentA, core.Strict.String(), options)
+			Expect(result).To(Equal(false))
+			Expect(output).Should(ContainSubstring(`"/id": null does not match uuid`))
+			Expect(output).Should(ContainSubstring(`"/count": -1 does not match nonNegativeInteger`))
+			Expect(output).ShouldNot(ContainSubstring("13:45:05"))
+		})
+
+		It("should return that are different when a value equal to primary does not match", func() {
+			documentA := []byte(`{"id": null}`)
+
+			result, output := json.CompareDocumentsWithOptions(documentA, documentA, core.Strict.String(), options)
+			Expect(result).To(Equal(false))
+			Expect(output).Should(Equal(`"/id": null does not match uuid`))
+		})
+	})
+
+	Describe("Matching values of Json documents", func() {
+		It("should replace matching values by primary values of elements with the same key", func() {
+			primary := []byte(`{"items": [{"sku": "A", "id": 1}, {"sku": "B", "id": 2}]}`)
+			candidate := []byte(`{"items": [{"sku": "B", "id": "0d1e2f3a-4b5c-6d7e-8f90-a1b2c3d4e5f6"}, {"sku": "A", "id": "x"}]}`)
+
+			options := matchers(map[string]string{"/items/*/id": "uuid"})
+			options.ArrayKeys = map[string]string{"/items": "sku"}
+
+			matched, mismatches, err := json.MatchValues(candidate, primary, options)
+			Expect(err).Should(Succeed())
+			Expect(matched).Should(MatchJSON(`{"items": [{"sku": "B", "id": 2}, {"sku": "A", "id": "x"}]}`))
+			Expect(mismatches).Should(ConsistOf(`"/items/1/id": "x" does not match uuid`))
+		})
+	})
+})
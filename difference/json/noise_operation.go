@@ -2,7 +2,10 @@ package json
 
 import (
 	"bytes"
+	jsonenc "encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	jsonpatchapplier "github.com/evanphx/json-patch"
 	"github.com/mattbaird/jsonpatch"
@@ -10,10 +13,17 @@ import (
 
 // NoiseOperation struct
 type NoiseOperation struct {
-	Patch []jsonpatch.JsonPatchOperation
+	Patch   []jsonpatch.JsonPatchOperation
+	Options Options
+	// Tolerant detection considers elements added or removed between primary and secondary as noise, instead of failing.
+	// These elements are dropped from primary and candidate when both contain them, and in case of array elements, the whole tail of the array is dropped.
+	// An element missing from primary or candidate is kept, so it is reported as a structural difference.
+	Tolerant bool
 }
 
-// Initialize with some json pointers
+// Initialize with some json pointers. Pointers can contain wildcards (*) to match any key or index, and recursive wildcards (**) to match any number of them,
+// like /items/*/updatedAt or /**/etag. JSONPath expressions starting with $, like $.orders[?(@.status=='PENDING')].eta, are supported too.
+// They are expanded against each document when noise is removed.
 func (nd *NoiseOperation) Initialize(pointers []string) {
 
 	for _, v := range pointers {
@@ -31,18 +41,29 @@ func (nd NoiseOperation) ContainsNoise() bool {
 // Detect Noise between documents
 func (nd *NoiseOperation) Detect(primary, secondary []byte) error {
 
-	patch, err := jsonpatch.CreatePatch(primary, secondary)
+	primary, secondary, err := nd.Options.prepare(primary, secondary)
 
 	if err != nil {
 		return err
 	}
 
-	newPatch, err := validatePatchToContainOnlyReplaceAndChangeValue(patch)
+	patch, err := jsonpatch.CreatePatch(primary, secondary)
 
 	if err != nil {
 		return err
 	}
 
+	var newPatch []jsonpatch.JsonPatchOperation
+	if nd.Tolerant {
+		newPatch = toleratePatch(patch)
+	} else {
+		newPatch, err = validatePatchToContainOnlyReplaceAndChangeValue(patch)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, v := range newPatch {
 		nd.Patch = append(nd.Patch, v)
 	}
@@ -67,26 +88,49 @@ func validatePatchToContainOnlyReplaceAndChangeValue(patch []jsonpatch.JsonPatch
 	return replacePatches, nil
 }
 
-// Remove noise from primary and candidate documents
+// toleratePatch changes the value of replace operations and transforms added and removed elements into remove operations, so they are dropped
+func toleratePatch(patch []jsonpatch.JsonPatchOperation) []jsonpatch.JsonPatchOperation {
+
+	var tolerantPatches []jsonpatch.JsonPatchOperation
+
+	for _, operation := range patch {
+		if operation.Operation == "replace" {
+			tolerantPatches = append(tolerantPatches, jsonpatch.NewPatch("replace", operation.Path, 0))
+		} else {
+			tolerantPatches = append(tolerantPatches, jsonpatch.NewPatch("remove", operation.Path, nil))
+		}
+	}
+
+	return tolerantPatches
+}
+
+// Remove noise from primary and candidate documents.
+// Noise pointers that are not present in a document are skipped, so a missing value is reported as a difference.
 func (nd *NoiseOperation) Remove(primary, candidate []byte) ([]byte, []byte, error) {
 
+	primary, candidate, err := nd.Options.prepare(primary, candidate)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	primaryWithoutNoise := primary
 	candidateWithoutNoise := candidate
 
 	if nd.ContainsNoise() {
-		patch, err := jsonpatchapplier.DecodePatch(nd.materializePatchOperations())
+		primary, candidate, err = nd.drop(primary, candidate)
 
 		if err != nil {
 			return nil, nil, err
 		}
 
-		primaryWithoutNoise, err = patch.Apply(primary)
+		primaryWithoutNoise, err = nd.apply(primary, candidate)
 
 		if err != nil {
 			return nil, nil, err
 		}
 
-		candidateWithoutNoise, err = patch.Apply(candidate)
+		candidateWithoutNoise, err = nd.apply(candidate, primary)
 
 		if err != nil {
 			return nil, nil, err
@@ -95,19 +139,264 @@ func (nd *NoiseOperation) Remove(primary, candidate []byte) ([]byte, []byte, err
 	return primaryWithoutNoise, candidateWithoutNoise, nil
 }
 
-func (nd NoiseOperation) materializePatchOperations() []byte {
-	var b bytes.Buffer
-	b.Write([]byte("["))
-	i := 0
+// Pointers returns the JSON pointers of the noise
+func (nd NoiseOperation) Pointers() []string {
+	var pointers []string
+	for _, operation := range nd.Patch {
+		pointers = append(pointers, operation.Path)
+	}
+	return pointers
+}
+
+// drop removes from both documents the elements of remove operations present in both of them
+func (nd NoiseOperation) drop(primary, candidate []byte) ([]byte, []byte, error) {
+
+	var pointers []string
+	for _, operation := range nd.Patch {
+		if operation.Operation == "remove" {
+			pointers = append(pointers, operation.Path)
+		}
+	}
+
+	if len(pointers) == 0 {
+		return primary, candidate, nil
+	}
+
+	primaryDocument, err := decode(primary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateDocument, err := decode(candidate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Elements missing from one document are not dropped from the other one, so a missing element is reported as a difference
+	var expandedPointers []string
+	for _, pointer := range pointers {
+		expanded, err := expandPointerInBoth(primaryDocument, candidateDocument, pointer)
+		if err != nil {
+			return nil, nil, err
+		}
+		expandedPointers = append(expandedPointers, expanded...)
+	}
+	pointers = expandedPointers
+
+	if len(pointers) == 0 {
+		return primary, candidate, nil
+	}
+
+	for _, pointer := range pointers {
+		primaryDocument, err = dropMatching(primaryDocument, pointer)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		candidateDocument, err = dropMatching(candidateDocument, pointer)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	droppedPrimary, err := jsonenc.Marshal(primaryDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	droppedCandidate, err := jsonenc.Marshal(candidateDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return droppedPrimary, droppedCandidate, nil
+}
+
+// apply the noise operations to the document. Noise pointers are expanded against the other document too, so both documents are masked equally.
+func (nd NoiseOperation) apply(document, other []byte) ([]byte, error) {
+	decodedDocument, err := decode(document)
+
+	if err != nil {
+		return nil, err
+	}
+
+	decodedOther, err := decode(other)
+
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := nd.materializePatchOperations(decodedDocument, decodedOther)
+
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := jsonpatchapplier.DecodePatch(operations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return patch.Apply(document)
+}
+
+// materializePatchOperations expands the pointers of replace operations against the document and the other document, so wildcards and
+// JSONPath expressions are replaced by the pointers of the values they select in any of them, and pointers without value in the document are skipped
+func (nd NoiseOperation) materializePatchOperations(document, other interface{}) ([]byte, error) {
+	var operations [][]byte
 	for _, operation := range nd.Patch {
-		patchOp, _ := operation.MarshalJSON()
-		b.Write(patchOp)
-		if i != len(nd.Patch)-1 {
-			b.Write([]byte(","))
+		if operation.Operation != "replace" {
+			continue
+		}
+
+		pointers, err := expandPointerOnBoth(document, other, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pointer := range pointers {
+			if !hasValue(document, pointer) {
+				continue
+			}
+
+			expandedOperation := jsonpatch.NewPatch("replace", pointer, 0)
+			patchOp, _ := expandedOperation.MarshalJSON()
+			operations = append(operations, patchOp)
 		}
-		i++
 	}
+
+	var b bytes.Buffer
+	b.Write([]byte("["))
+	b.Write(bytes.Join(operations, []byte(",")))
 	b.Write([]byte("]"))
 
-	return b.Bytes()
+	return b.Bytes(), nil
+}
+
+// OriginalPointers returns the noise pointers in the form of the original documents, since noise is detected in documents prepared with the options.
+// Elements of keyed arrays, indexed by key=value when documents are prepared, are selected with a JSONPath filter on their key, and elements of
+// unordered arrays, sorted when documents are prepared, are selected with a wildcard since their index is different in each document.
+// Primary document is used to know the type of the keys and which tokens are array indexes.
+func (nd NoiseOperation) OriginalPointers(primary []byte) ([]string, error) {
+
+	if nd.Options.isEmpty() {
+		return nd.Pointers(), nil
+	}
+
+	prepared, _, err := nd.Options.prepare(primary, primary)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := decode(prepared)
+	if err != nil {
+		return nil, err
+	}
+
+	unorderedArrays := compilePointerPatterns(nd.Options.UnorderedArrays)
+	keyedArrays := compileKeyedArrays(nd.Options.ArrayKeys)
+
+	var pointers []string
+	found := make(map[string]bool)
+	for _, pointer := range nd.Pointers() {
+		// Different elements of unordered arrays are translated to the same wildcard
+		original := originalPointer(document, pointer, unorderedArrays, keyedArrays)
+		if !found[original] {
+			found[original] = true
+			pointers = append(pointers, original)
+		}
+	}
+
+	return pointers, nil
+}
+
+// originalPointer translates a pointer of the prepared document to a JSONPath expression if it selects elements of keyed or unordered arrays,
+// or returns it as it is otherwise
+func originalPointer(document interface{}, pointer string, unorderedArrays []pointerPattern, keyedArrays []keyedArray) string {
+	var path, segments []string
+	translated := false
+	value := document
+
+	for _, token := range compilePointerPattern(pointer) {
+		segment, ok := quoteName(token)
+		if !ok {
+			// Names that cannot be quoted without escaped characters are not supported by JSONPath expressions
+			return pointer
+		}
+
+		switch node := value.(type) {
+		case map[string]interface{}:
+			child := node[token]
+			if key, ok := findArrayKey(keyedArrays, path); ok && isKeyToken(token, key, child) {
+				segment, translated = keyFilter(key, child), true
+			}
+			value = child
+		case []interface{}:
+			segment = "[" + token + "]"
+			if matchesAny(unorderedArrays, path) {
+				segment, translated = "[*]", true
+			}
+			value = nil
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node) {
+				value = node[index]
+			}
+		default:
+			value = nil
+		}
+
+		path = append(path, token)
+		segments = append(segments, segment)
+	}
+
+	if !translated {
+		return pointer
+	}
+	return jsonPathRoot + strings.Join(segments, "")
+}
+
+// quoteName returns the bracket selector of the name, quoted with single or double quotes so it does not need escaped characters
+func quoteName(name string) (string, bool) {
+	switch {
+	case strings.ContainsRune(name, '\\'):
+		return "", false
+	case !strings.ContainsRune(name, '\''):
+		return "['" + name + "']", true
+	case !strings.ContainsRune(name, '"'):
+		return `["` + name + `"]`, true
+	}
+	return "", false
+}
+
+// isKeyToken returns true if the token is the key=value index of the element in a keyed array
+func isKeyToken(token, key string, element interface{}) bool {
+	object, ok := element.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	keyValue, ok := scalarToString(object[key])
+	return ok && token == key+"="+keyValue
+}
+
+// keyFilter returns the JSONPath filter selecting the element of a keyed array by its key, or a wildcard if the key cannot be expressed as a filter
+func keyFilter(key string, element interface{}) string {
+	for i := 0; i < len(key); i++ {
+		if !isNameCharacter(key[i]) {
+			return "[*]"
+		}
+	}
+
+	var literal string
+	switch keyValue := element.(map[string]interface{})[key].(type) {
+	case string:
+		if strings.ContainsAny(keyValue, `'\`) {
+			return "[*]"
+		}
+		literal = "'" + keyValue + "'"
+	default:
+		literal = canonical(keyValue)
+	}
+
+	return "[?(@." + key + " == " + literal + ")]"
 }
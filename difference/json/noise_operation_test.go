@@ -90,6 +90,193 @@ var _ = Describe("Noise Operation", func() {
 		})
 	})
 
+	Describe("Finding for Noise between calls with unordered arrays", func() {
+		Context("Valid request", func() {
+			It("should return only noise operations of elements that are different", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered-noise.json")
+
+				noiseOperation := json.NoiseOperation{Options: json.Options{UnorderedArrays: []string{"/items", "/users/*/roles"}}}
+
+				error := noiseOperation.Detect(documentA, documentB)
+
+				Expect(error).Should(Succeed())
+				Expect(noiseOperation.Patch).Should(HaveLen(1))
+				Expect(noiseOperation.Patch).Should(ContainElement(jsonpatch.NewPatch("replace", "/users/1/name", 0)))
+			})
+
+			It("should return both documents equal after removing noise", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered-noise.json")
+
+				noiseOperation := json.NoiseOperation{Options: json.Options{UnorderedArrays: []string{"/items", "/users/*/roles"}}}
+				noiseOperation.Detect(documentA, documentB)
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, _ := json.CompareDocumentsWithOptions(candidate, primary, "Strict", noiseOperation.Options)
+
+				Expect(result).Should(Equal(true))
+			})
+		})
+	})
+
+	Describe("Returning noise pointers in the form of original documents", func() {
+		Context("Valid request", func() {
+			It("should return elements of keyed arrays as filters on their key", func() {
+				documentA := loadFromFile("test_fixtures/document-e.json")
+				documentB := loadFromFile("test_fixtures/document-e-changed.json")
+
+				noiseOperation := json.NoiseOperation{Options: json.Options{ArrayKeys: map[string]string{"/orders": "sku"}}}
+				Expect(noiseOperation.Detect(documentA, documentB)).Should(Succeed())
+
+				pointers, err := noiseOperation.OriginalPointers(documentA)
+
+				Expect(err).Should(Succeed())
+				Expect(pointers).Should(Equal([]string{"$['orders'][?(@.sku == 'B-2')]['quantity']"}))
+			})
+
+			It("should return elements of unordered arrays as wildcards", func() {
+				documentA := loadFromFile("test_fixtures/document-k.json")
+				documentB := loadFromFile("test_fixtures/document-k-changed.json")
+
+				noiseOperation := json.NoiseOperation{Options: json.Options{UnorderedArrays: []string{"/post/tags"}}}
+				Expect(noiseOperation.Detect(documentA, documentB)).Should(Succeed())
+
+				pointers, err := noiseOperation.OriginalPointers(documentA)
+
+				Expect(err).Should(Succeed())
+				Expect(pointers).Should(Equal([]string{"$['post']['tags'][*]"}))
+			})
+
+			It("should return pointers out of keyed and unordered arrays as they are", func() {
+				documentA := loadFromFile("test_fixtures/document-d.json")
+				documentB := loadFromFile("test_fixtures/document-d-reordered-noise.json")
+
+				noiseOperation := json.NoiseOperation{Options: json.Options{UnorderedArrays: []string{"/items", "/users/*/roles"}}}
+				Expect(noiseOperation.Detect(documentA, documentB)).Should(Succeed())
+
+				pointers, err := noiseOperation.OriginalPointers(documentA)
+
+				Expect(err).Should(Succeed())
+				Expect(pointers).Should(Equal([]string{"/users/1/name"}))
+			})
+		})
+	})
+
+	Describe("Finding for Noise between calls with structural variation", func() {
+		Context("Strict detection", func() {
+			It("should return error if elements are removed", func() {
+				documentA := loadFromFile("test_fixtures/document-h-optional.json")
+				documentB := loadFromFile("test_fixtures/document-h.json")
+
+				noiseOperation := json.NoiseOperation{}
+
+				error := noiseOperation.Detect(documentA, documentB)
+				Expect(error).Should(HaveOccurred())
+			})
+		})
+		Context("Tolerant detection", func() {
+			It("should return remove operations for removed elements", func() {
+				documentA := loadFromFile("test_fixtures/document-h-optional.json")
+				documentB := loadFromFile("test_fixtures/document-h.json")
+
+				noiseOperation := json.NoiseOperation{Tolerant: true}
+
+				error := noiseOperation.Detect(documentA, documentB)
+
+				Expect(error).Should(Succeed())
+				Expect(noiseOperation.Patch).Should(HaveLen(4))
+				Expect(noiseOperation.Patch).Should(ContainElement(jsonpatch.NewPatch("replace", "/requestedAt", 0)))
+				Expect(noiseOperation.Patch).Should(ContainElement(jsonpatch.NewPatch("remove", "/promotion", nil)))
+				Expect(noiseOperation.Patch).Should(ContainElement(jsonpatch.NewPatch("remove", "/recommendations/2", nil)))
+				Expect(noiseOperation.Patch).Should(ContainElement(jsonpatch.NewPatch("remove", "/recommendations/3", nil)))
+			})
+
+			It("should return both documents equal after dropping removed elements", func() {
+				documentA := loadFromFile("test_fixtures/document-h-optional.json")
+				documentB := loadFromFile("test_fixtures/document-h.json")
+				documentC := loadFromFile("test_fixtures/document-h-candidate.json")
+
+				noiseOperation := json.NoiseOperation{Tolerant: true}
+				noiseOperation.Detect(documentA, documentB)
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentC)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, _ := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(true))
+			})
+
+			It("should return documents not equal if elements out of noise are different", func() {
+				documentA := loadFromFile("test_fixtures/document-h-optional.json")
+				documentB := loadFromFile("test_fixtures/document-h.json")
+				documentC := loadFromFile("test_fixtures/document-h-renamed.json")
+
+				noiseOperation := json.NoiseOperation{Tolerant: true}
+				noiseOperation.Detect(documentA, documentB)
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentC)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, diff := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(false))
+				Expect(diff).Should(ContainSubstring("Alexandra"))
+				Expect(diff).Should(ContainSubstring("music"))
+			})
+
+			It("should return documents not equal if candidate lacks removed elements", func() {
+				documentA := loadFromFile("test_fixtures/document-h-optional.json")
+				documentB := loadFromFile("test_fixtures/document-h.json")
+
+				noiseOperation := json.NoiseOperation{Tolerant: true}
+				noiseOperation.Detect(documentA, documentB)
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, diff := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(false))
+				Expect(diff).Should(ContainSubstring("promotion"))
+			})
+
+			It("should return documents not equal if candidate lacks learned noise", func() {
+				documentA := loadFromFile("test_fixtures/document-h-candidate.json")
+				documentC := loadFromFile("test_fixtures/document-h.json")
+
+				noiseOperation := json.NoiseOperation{Tolerant: true}
+				noiseOperation.Initialize([]string{"/requestedAt", "/promotion", "/recommendations/2"})
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentC)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, diff := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(false))
+				Expect(diff).Should(ContainSubstring("promotion"))
+			})
+		})
+	})
+
 	Describe("Removing Noise from Documents", func() {
 		Context("A primary and candidate without noise", func() {
 			It("should return both documents without any change", func() {
@@ -193,6 +380,85 @@ var _ = Describe("Noise Operation", func() {
 				Expect(result).Should(Equal(false))
 
 			})
+			It("should return both documents equal using wildcard pointers", func() {
+
+				documentA := loadFromFile("test_fixtures/document-i.json")
+				documentB := loadFromFile("test_fixtures/document-i-changed.json")
+
+				noiseOperation := json.NoiseOperation{}
+				noiseOperation.Initialize([]string{"/items/*/updatedAt", "/**/etag"})
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, _ := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(true))
+
+			})
+			It("should return both documents not equal if wildcard pointers do not match all noise", func() {
+
+				documentA := loadFromFile("test_fixtures/document-i.json")
+				documentB := loadFromFile("test_fixtures/document-i-changed.json")
+
+				noiseOperation := json.NoiseOperation{}
+				noiseOperation.Initialize([]string{"/items/*/updatedAt", "/*/etag"})
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, diff := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(false))
+				Expect(diff).Should(ContainSubstring("d1"))
+				Expect(diff).ShouldNot(ContainSubstring("13:45:05"))
+
+			})
+			It("should skip wildcard pointers without values in documents", func() {
+
+				documentA := loadFromFile("test_fixtures/document-a.json")
+				documentB := loadFromFile("test_fixtures/document-a-change-date.json")
+
+				noiseOperation := json.NoiseOperation{}
+				noiseOperation.Initialize([]string{"/now/*", "/items/*/updatedAt", "/**/etag", "/urls/*/name"})
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, _ := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(true))
+
+			})
+			It("should skip noise not present in documents", func() {
+
+				documentA := loadFromFile("test_fixtures/document-a.json")
+				documentB := loadFromFile("test_fixtures/document-a-change-date.json")
+
+				noiseOperation := json.NoiseOperation{}
+				noiseOperation.Initialize([]string{"/now/epoch", "/now/iso8601", "/now/rfc2822", "/now/rfc3339", "/now/timezone", "/urls/10"})
+
+				primary, candidate, err := noiseOperation.Remove(documentA, documentB)
+
+				if err != nil {
+					Fail(fmt.Sprintf("Failing removing noise. Reason: %q", err))
+				}
+
+				result, _ := json.CompareDocuments(candidate, primary, "Strict")
+
+				Expect(result).Should(Equal(true))
+				Expect(noiseOperation.Pointers()).Should(HaveLen(6))
+
+			})
 		})
 	})
 })
@@ -0,0 +1,81 @@
+package json
+
+import (
+	jsonenc "encoding/json"
+)
+
+// Options to customize how documents are compared and how noise is detected
+type Options struct {
+	// UnorderedArrays are the JSON pointers of arrays compared as multisets. Any token can be * to match any key or index.
+	UnorderedArrays []string
+	// ArrayKeys are the JSON pointers of arrays of objects, and the identity field of the objects, used to match elements by key instead of by index.
+	ArrayKeys map[string]string
+	// Tolerances are the numeric tolerances indexed by JSON pointer. Any token can be * to match any key or index.
+	Tolerances map[string]Tolerance
+	// DefaultTolerance is applied to numbers not matching any of the tolerances pointers.
+	DefaultTolerance Tolerance
+	// ValueMatchers are the matchers that candidate values must match instead of being equal to primary values, indexed by JSON pointer.
+	// Any token can be * to match any key or index, and pointers can also be JSONPath expressions evaluated against each candidate document.
+	// They are applied by MatchValues, CompareDocumentsWithOptions and DiffDocumentsWithOptions but not when noise is detected or removed.
+	ValueMatchers map[string]ValueMatcher
+	// Mask transforms string values before comparing them, for example to replace texts that change on every response.
+	Mask func(string) string
+	// alignKeyedArrays reorders keyed arrays by key instead of transforming them to objects indexed by key, so pointers keep primary indexes
+	alignKeyedArrays bool
+	// primaryOrder stores the original indexes of the elements of primary unordered arrays once they are sorted, if it is not nil
+	primaryOrder map[string][]int
+}
+
+func (options Options) isEmpty() bool {
+	return len(options.UnorderedArrays) == 0 && len(options.ArrayKeys) == 0 && len(options.Tolerances) == 0 && options.DefaultTolerance.IsZero() && options.Mask == nil
+}
+
+// prepare transforms primary and other document (candidate or secondary) so they can be compared element by element.
+// Value matchers are not applied here, they are validated once against the original candidate with MatchValues.
+func (options Options) prepare(primary, other []byte) ([]byte, []byte, error) {
+
+	if options.isEmpty() {
+		return primary, other, nil
+	}
+
+	primaryDocument, err := decode(primary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	otherDocument, err := decode(other)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if options.Mask != nil {
+		primaryDocument = maskStrings(primaryDocument, options.Mask)
+		otherDocument = maskStrings(otherDocument, options.Mask)
+	}
+
+	unorderedArrays := compilePointerPatterns(options.UnorderedArrays)
+	keyedArrays := compileKeyedArrays(options.ArrayKeys)
+
+	primaryDocument = sortUnorderedArrays(primaryDocument, nil, unorderedArrays, options.primaryOrder)
+	otherDocument = sortUnorderedArrays(otherDocument, nil, unorderedArrays, nil)
+	if options.alignKeyedArrays {
+		otherDocument = alignKeyedArrays(primaryDocument, otherDocument, nil, keyedArrays)
+	} else {
+		primaryDocument = indexKeyedArrays(primaryDocument, nil, keyedArrays)
+		otherDocument = indexKeyedArrays(otherDocument, nil, keyedArrays)
+	}
+	otherDocument = alignUnorderedArrays(primaryDocument, otherDocument, nil, unorderedArrays)
+	otherDocument = applyTolerances(primaryDocument, otherDocument, nil, compileToleranceRules(options.Tolerances), options.DefaultTolerance)
+
+	preparedPrimary, err := jsonenc.Marshal(primaryDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preparedOther, err := jsonenc.Marshal(otherDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return preparedPrimary, preparedOther, nil
+}
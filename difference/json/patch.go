@@ -0,0 +1,184 @@
+package json
+
+import (
+	jsonenc "encoding/json"
+	"sort"
+	"strconv"
+)
+
+// Operation is a JSON Patch (RFC 6902) operation that transforms primary document into candidate document.
+// Primary member is not part of the RFC and contains the primary value of replaced and removed elements.
+type Operation struct {
+	Op      string             `json:"op"`
+	Path    string             `json:"path"`
+	Value   jsonenc.RawMessage `json:"value,omitempty"`
+	Primary jsonenc.RawMessage `json:"primary,omitempty"`
+}
+
+const (
+	addOperation     = "add"
+	removeOperation  = "remove"
+	replaceOperation = "replace"
+)
+
+// DiffDocuments returns the list of operations that transforms original document into candidate according to configured difference
+func DiffDocuments(candidate, original []byte, difference string) ([]Operation, error) {
+	return DiffDocumentsWithOptions(candidate, original, difference, Options{})
+}
+
+// DiffDocumentsWithOptions returns the list of operations that transforms original document into candidate according to configured difference and options.
+// In Subset mode, elements only present in candidate are not reported, and in Schema mode only elements with different type are reported.
+// Elements of keyed arrays are matched by key, and elements of unordered arrays are sorted, but they are reported with the index of the element
+// in the original primary document so operations can be applied to primary. Numbers are compared by their value, so 1.0 is equal to 1.
+func DiffDocumentsWithOptions(candidate, original []byte, difference string, options Options) ([]Operation, error) {
+
+	candidate, _, err := MatchValues(candidate, original, options)
+	if err != nil {
+		return nil, err
+	}
+
+	options.alignKeyedArrays = true
+	options.primaryOrder = make(map[string][]int)
+	original, candidate, err = options.prepare(original, candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	candidateDocument, err := decode(candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	originalDocument, err := decode(original)
+	if err != nil {
+		return nil, err
+	}
+
+	differ := patchDiffer{
+		ignoreAdditions: difference == "Subset",
+		onlyTypes:       difference == "Schema",
+		primaryOrder:    options.primaryOrder,
+	}
+	differ.diff(originalDocument, candidateDocument, nil)
+
+	return differ.operations, nil
+}
+
+type patchDiffer struct {
+	ignoreAdditions bool
+	onlyTypes       bool
+	primaryOrder    map[string][]int
+	operations      []Operation
+}
+
+func (differ *patchDiffer) diff(original, candidate interface{}, path []string) {
+
+	if typeOf(original) != typeOf(candidate) {
+		differ.replace(path, original, candidate)
+		return
+	}
+
+	switch originalValue := original.(type) {
+	case map[string]interface{}:
+		candidateValue := candidate.(map[string]interface{})
+		for _, key := range unionOfKeys(originalValue, candidateValue) {
+			originalChild, inOriginal := originalValue[key]
+			candidateChild, inCandidate := candidateValue[key]
+			switch {
+			case inOriginal && inCandidate:
+				differ.diff(originalChild, candidateChild, appendToken(path, key))
+			case inOriginal:
+				differ.remove(appendToken(path, key), originalChild)
+			default:
+				differ.add(appendToken(path, key), candidateChild)
+			}
+		}
+	case []interface{}:
+		differ.diffArrays(originalValue, candidate.([]interface{}), path)
+	default:
+		if !differ.onlyTypes && !equalValues(original, candidate) {
+			differ.replace(path, original, candidate)
+		}
+	}
+}
+
+func (differ *patchDiffer) diffArrays(original, candidate []interface{}, path []string) {
+
+	common := len(original)
+	if len(candidate) < common {
+		common = len(candidate)
+	}
+
+	for i := 0; i < common; i++ {
+		differ.diff(original[i], candidate[i], appendToken(path, strconv.Itoa(i)))
+	}
+
+	for i := common; i < len(candidate); i++ {
+		// In Schema mode, remaining elements are only reported if their type is not the type of the other array elements
+		if differ.onlyTypes && len(original) > 0 && typeOf(original[0]) == typeOf(candidate[i]) {
+			continue
+		}
+		differ.add(appendToken(path, strconv.Itoa(i)), candidate[i])
+	}
+
+	var removed []int
+	for i := common; i < len(original); i++ {
+		if differ.onlyTypes && len(candidate) > 0 && typeOf(candidate[0]) == typeOf(original[i]) {
+			continue
+		}
+		removed = append(removed, i)
+	}
+
+	// Elements are removed from last to first of the original primary array so operations can be applied in order
+	indexes := differ.primaryOrder[toPointer(differ.originalPath(path))]
+	originalIndex := func(i int) int {
+		if i < len(indexes) {
+			return indexes[i]
+		}
+		return i
+	}
+	sort.SliceStable(removed, func(a, b int) bool {
+		return originalIndex(removed[a]) > originalIndex(removed[b])
+	})
+
+	for _, i := range removed {
+		differ.remove(appendToken(path, strconv.Itoa(i)), original[i])
+	}
+}
+
+// originalPath translates the path of an element of the prepared primary document to the path of the element in the original primary document,
+// since elements of unordered arrays are sorted when documents are prepared
+func (differ *patchDiffer) originalPath(path []string) []string {
+	original := make([]string, 0, len(path))
+
+	for _, token := range path {
+		if indexes, ok := differ.primaryOrder[toPointer(original)]; ok {
+			if index, err := strconv.Atoi(token); err == nil && index < len(indexes) {
+				token = strconv.Itoa(indexes[index])
+			}
+		}
+		original = append(original, token)
+	}
+
+	return original
+}
+
+func (differ *patchDiffer) add(path []string, candidate interface{}) {
+	if differ.ignoreAdditions {
+		return
+	}
+	differ.operations = append(differ.operations, Operation{Op: addOperation, Path: toPointer(differ.originalPath(path)), Value: rawValue(candidate)})
+}
+
+func (differ *patchDiffer) remove(path []string, original interface{}) {
+	differ.operations = append(differ.operations, Operation{Op: removeOperation, Path: toPointer(differ.originalPath(path)), Primary: rawValue(original)})
+}
+
+func (differ *patchDiffer) replace(path []string, original, candidate interface{}) {
+	differ.operations = append(differ.operations, Operation{Op: replaceOperation, Path: toPointer(differ.originalPath(path)), Value: rawValue(candidate), Primary: rawValue(original)})
+}
+
+func rawValue(value interface{}) jsonenc.RawMessage {
+	// null is kept as raw value so it is not omitted
+	return jsonenc.RawMessage(canonical(value))
+}
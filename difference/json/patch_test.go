@@ -0,0 +1,156 @@
+package json_test
+
+import (
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Json Patch Difference", func() {
+
+	Describe("Diff Two Equal Json documents", func() {
+		Context("With strict mode", func() {
+			It("should return no operations", func() {
+				documentA := loadFromFile("test_fixtures/document-a.json")
+
+				operations, err := json.DiffDocuments(documentA, documentA, core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Diff Two Different Json documents", func() {
+		Context("With strict mode", func() {
+			It("should return replace operation with both values", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-update-type.json")
+
+				operations, err := json.DiffDocuments(documentB, documentA, core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(HaveLen(1))
+				Expect(operations[0].Op).Should(Equal("replace"))
+				Expect(operations[0].Path).Should(Equal("/b/c"))
+				Expect(string(operations[0].Primary)).Should(Equal("3"))
+				Expect(string(operations[0].Value)).Should(Equal(`"Hello Worls"`))
+			})
+
+			It("should return add operation when a key is added", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-update.json")
+
+				operations, err := json.DiffDocuments(documentB, documentA, core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "add", Path: "/e", Value: []byte("5")},
+				}))
+			})
+
+			It("should return remove operation when a key is removed", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-update.json")
+
+				operations, err := json.DiffDocuments(documentA, documentB, core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "remove", Path: "/e", Primary: []byte("5")},
+				}))
+			})
+
+			It("should remove array elements from last to first", func() {
+				operations, err := json.DiffDocuments([]byte(`{"a": [1]}`), []byte(`{"a": [1, 2, 3]}`), core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "remove", Path: "/a/2", Primary: []byte("3")},
+					json.Operation{Op: "remove", Path: "/a/1", Primary: []byte("2")},
+				}))
+			})
+
+			It("should compare numbers by their value", func() {
+				operations, err := json.DiffDocuments([]byte(`{"a": 1.0, "b": 1e2}`), []byte(`{"a": 1, "b": 100}`), core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(BeEmpty())
+			})
+
+			It("should keep null values", func() {
+				operations, err := json.DiffDocuments([]byte(`{"a": null}`), []byte(`{"a": 1}`), core.Strict.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(HaveLen(1))
+				Expect(string(operations[0].Value)).Should(Equal("null"))
+			})
+
+			It("should return error when document is invalid", func() {
+				_, err := json.DiffDocuments([]byte(`{"a":`), []byte(`{"a": 1}`), core.Strict.String())
+				Expect(err).ShouldNot(Succeed())
+			})
+		})
+
+		Context("With subset mode", func() {
+			It("should not return add operations", func() {
+				documentA := loadFromFile("test_fixtures/document-c.json")
+				documentB := loadFromFile("test_fixtures/document-c-update.json")
+
+				operations, err := json.DiffDocuments(documentB, documentA, core.Subset.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(BeEmpty())
+			})
+		})
+
+		Context("With schema mode", func() {
+			It("should only return operations of elements with different type", func() {
+				operations, err := json.DiffDocuments([]byte(`{"a": 2, "b": "c"}`), []byte(`{"a": 1, "b": 3}`), core.Schema.String())
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(HaveLen(1))
+				Expect(operations[0].Path).Should(Equal("/b"))
+			})
+		})
+
+		Context("With unordered arrays", func() {
+			It("should report elements with the index of the original primary array", func() {
+				operations, err := json.DiffDocumentsWithOptions([]byte(`{"tags": ["b"]}`), []byte(`{"tags": ["c", "a", "b"]}`), core.Strict.String(), json.Options{UnorderedArrays: []string{"/tags"}})
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "replace", Path: "/tags/1", Value: []byte(`"b"`), Primary: []byte(`"a"`)},
+					json.Operation{Op: "remove", Path: "/tags/2", Primary: []byte(`"b"`)},
+					json.Operation{Op: "remove", Path: "/tags/0", Primary: []byte(`"c"`)},
+				}))
+			})
+
+			It("should report elements of nested unordered arrays with their original indexes", func() {
+				operations, err := json.DiffDocumentsWithOptions([]byte(`{"groups": [["x"], ["b", "z"]]}`), []byte(`{"groups": [["c", "b"], ["x"]]}`), core.Strict.String(), json.Options{UnorderedArrays: []string{"/groups", "/groups/*"}})
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "replace", Path: "/groups/0/0", Value: []byte(`"z"`), Primary: []byte(`"c"`)},
+				}))
+			})
+		})
+
+		Context("With keyed arrays", func() {
+			It("should report the changed element matched by key with the index of primary element", func() {
+				documentA := loadFromFile("test_fixtures/document-e.json")
+				documentB := loadFromFile("test_fixtures/document-e-changed.json")
+
+				operations, err := json.DiffDocumentsWithOptions(documentB, documentA, core.Strict.String(), json.Options{ArrayKeys: map[string]string{"/orders": "sku"}})
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "replace", Path: "/orders/1/quantity", Value: []byte("5"), Primary: []byte("2")},
+				}))
+			})
+
+			It("should report elements not present in primary at the end of the array", func() {
+				documentA := loadFromFile("test_fixtures/document-e.json")
+				documentB := loadFromFile("test_fixtures/document-e-inserted.json")
+
+				operations, err := json.DiffDocumentsWithOptions(documentB, documentA, core.Strict.String(), json.Options{ArrayKeys: map[string]string{"/orders": "sku"}})
+				Expect(err).Should(Succeed())
+				Expect(operations).Should(Equal([]json.Operation{
+					json.Operation{Op: "add", Path: "/orders/3", Value: []byte(`{"quantity":9,"sku":"Z-9"}`)},
+				}))
+			})
+		})
+	})
+
+})
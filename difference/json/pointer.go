@@ -0,0 +1,268 @@
+package json
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	anyToken       = "*"
+	recursiveToken = "**"
+)
+
+// pointerPattern is a JSON pointer where any token can be a wildcard (*) matching any key or index,
+// or a recursive wildcard (**) matching any number of keys or indexes, including none
+type pointerPattern []string
+
+func compilePointerPatterns(pointers []string) []pointerPattern {
+	var patterns []pointerPattern
+	for _, pointer := range pointers {
+		patterns = append(patterns, compilePointerPattern(pointer))
+	}
+	return patterns
+}
+
+func compilePointerPattern(pointer string) pointerPattern {
+	if len(pointer) == 0 {
+		return pointerPattern{}
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = unescapePointerToken(token)
+	}
+	return pointerPattern(tokens)
+}
+
+func (pattern pointerPattern) matches(path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == recursiveToken {
+		for i := 0; i <= len(path); i++ {
+			if pattern[1:].matches(path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 || (pattern[0] != anyToken && pattern[0] != path[0]) {
+		return false
+	}
+
+	return pattern[1:].matches(path[1:])
+}
+
+func matchesAny(patterns []pointerPattern, path []string) bool {
+	for _, pattern := range patterns {
+		if pattern.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPointer returns the pointers of all values of the document matching the pointer, which can contain wildcards or be a JSONPath expression.
+// A pointer without any matching value returns no pointers.
+func expandPointer(document interface{}, pointer string) ([]string, error) {
+	if IsJSONPath(pointer) {
+		path, err := compileJSONPath(pointer)
+		if err != nil {
+			return nil, err
+		}
+		return path.pointers(document), nil
+	}
+
+	var pointers []string
+	found := make(map[string]bool)
+
+	expandPattern(document, compilePointerPattern(pointer), nil, func(path []string) {
+		// Recursive wildcards can match the same value more than once
+		expanded := toPointer(path)
+		if !found[expanded] {
+			found[expanded] = true
+			pointers = append(pointers, expanded)
+		}
+	})
+
+	return pointers, nil
+}
+
+// expandPointerOnBoth returns the union of the pointers expanded against both documents, so a value selected in one document is also selected
+// in the other one, for example when a JSONPath filter only matches the element of one of them
+func expandPointerOnBoth(document, otherDocument interface{}, pointer string) ([]string, error) {
+	pointers, err := expandPointer(document, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	otherPointers, err := expandPointer(otherDocument, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for _, expanded := range pointers {
+		found[expanded] = true
+	}
+
+	for _, expanded := range otherPointers {
+		if !found[expanded] {
+			found[expanded] = true
+			pointers = append(pointers, expanded)
+		}
+	}
+
+	return pointers, nil
+}
+
+// hasValue returns true if the document contains a value for the pointer
+func hasValue(document interface{}, pointer string) bool {
+	found := false
+	expandPattern(document, compilePointerPattern(pointer), nil, func(path []string) {
+		found = true
+	})
+	return found
+}
+
+func expandPattern(document interface{}, pattern pointerPattern, path []string, expanded func(path []string)) {
+	if len(pattern) == 0 {
+		expanded(path)
+		return
+	}
+
+	token := pattern[0]
+
+	if token == recursiveToken {
+		expandPattern(document, pattern[1:], path, expanded)
+		forEachChild(document, func(key string, child interface{}) {
+			expandPattern(child, pattern, appendToken(path, key), expanded)
+		})
+		return
+	}
+
+	forEachChild(document, func(key string, child interface{}) {
+		if token == anyToken || token == key {
+			expandPattern(child, pattern[1:], appendToken(path, key), expanded)
+		}
+	})
+}
+
+// forEachChild calls the function with each key or index of the document, in order
+func forEachChild(document interface{}, function func(key string, child interface{})) {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			function(key, value[key])
+		}
+	case []interface{}:
+		for i, child := range value {
+			function(strconv.Itoa(i), child)
+		}
+	}
+}
+
+// expandPointerInBoth returns the pointers of the values matching the pointer that are present in both documents
+func expandPointerInBoth(document, otherDocument interface{}, pointer string) ([]string, error) {
+	pointers, err := expandPointer(document, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	otherPointers, err := expandPointer(otherDocument, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for _, expanded := range otherPointers {
+		found[expanded] = true
+	}
+
+	var common []string
+	for _, expanded := range pointers {
+		if found[expanded] {
+			common = append(common, expanded)
+		}
+	}
+
+	return common, nil
+}
+
+// dropMatching removes from the document all values matching the pointer, which can contain wildcards or be a JSONPath expression
+func dropMatching(document interface{}, pointer string) (interface{}, error) {
+	expanded, err := expandPointer(document, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Last values are dropped first, so dropping an array element does not change the pointers of the previous ones
+	for i := len(expanded) - 1; i >= 0; i-- {
+		document = dropPointer(document, compilePointerPattern(expanded[i]))
+	}
+
+	return document, nil
+}
+
+// dropPointer removes the value of the pointer from the document. If the value is an element of an array, the array is truncated from this element.
+func dropPointer(document interface{}, tokens []string) interface{} {
+	if len(tokens) == 0 {
+		return document
+	}
+
+	switch value := document.(type) {
+	case map[string]interface{}:
+		child, ok := value[tokens[0]]
+		if !ok {
+			return document
+		}
+		if len(tokens) == 1 {
+			delete(value, tokens[0])
+		} else {
+			value[tokens[0]] = dropPointer(child, tokens[1:])
+		}
+	case []interface{}:
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil || index < 0 || index >= len(value) {
+			return document
+		}
+		if len(tokens) == 1 {
+			return value[:index]
+		}
+		value[index] = dropPointer(value[index], tokens[1:])
+	}
+
+	return document
+}
+
+func toPointer(path []string) string {
+	var pointer string
+	for _, token := range path {
+		pointer += "/" + escapePointerToken(token)
+	}
+	return pointer
+}
+
+func appendToken(path []string, token string) []string {
+	// A new slice is created to not share backing array between siblings
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, token)
+}
+
+func escapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func unescapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
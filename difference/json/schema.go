@@ -0,0 +1,125 @@
+package json
+
+import (
+	"bytes"
+	jsonenc "encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const undefinedType = "undefined"
+
+// compareSchemas checks that both documents have the same keys, nesting and value types ignoring the values.
+// It returns the list of mismatches in the form of `"pointer": primary type => candidate type`
+func compareSchemas(candidate, original []byte) (bool, string) {
+
+	candidateDocument, err := decode(candidate)
+	if err != nil {
+		return false, "first argument is invalid json"
+	}
+
+	originalDocument, err := decode(original)
+	if err != nil {
+		return false, "second argument is invalid json"
+	}
+
+	var mismatches []string
+	compareSchema(originalDocument, candidateDocument, "", &mismatches)
+
+	return len(mismatches) == 0, strings.Join(mismatches, "\n")
+}
+
+func compareSchema(original, candidate interface{}, pointer string, mismatches *[]string) {
+
+	originalType := typeOf(original)
+	candidateType := typeOf(candidate)
+
+	if originalType != candidateType {
+		*mismatches = append(*mismatches, fmt.Sprintf(`"%s": %s => %s`, pointer, originalType, candidateType))
+		return
+	}
+
+	switch originalValue := original.(type) {
+	case map[string]interface{}:
+		candidateValue := candidate.(map[string]interface{})
+		for _, key := range unionOfKeys(originalValue, candidateValue) {
+			compareSchema(valueOrUndefined(originalValue, key), valueOrUndefined(candidateValue, key), pointer+"/"+escapePointerToken(key), mismatches)
+		}
+	case []interface{}:
+		candidateValue := candidate.([]interface{})
+		// Arrays might have different length so when one of them is longer, remaining elements are checked against first element of the other one
+		for i := 0; i < maximum(len(originalValue), len(candidateValue)); i++ {
+			elementPointer := fmt.Sprintf("%s/%d", pointer, i)
+			switch {
+			case i < len(originalValue) && i < len(candidateValue):
+				compareSchema(originalValue[i], candidateValue[i], elementPointer, mismatches)
+			case i < len(originalValue) && len(candidateValue) > 0:
+				compareSchema(originalValue[i], candidateValue[0], elementPointer, mismatches)
+			case i < len(candidateValue) && len(originalValue) > 0:
+				compareSchema(originalValue[0], candidateValue[i], elementPointer, mismatches)
+			}
+		}
+	}
+
+}
+
+type undefined struct{}
+
+func valueOrUndefined(document map[string]interface{}, key string) interface{} {
+	if value, ok := document[key]; ok {
+		return value
+	}
+	return undefined{}
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case jsonenc.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return undefinedType
+}
+
+func unionOfKeys(a, b map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	var sortedKeys []string
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	return sortedKeys
+}
+
+func decode(document []byte) (interface{}, error) {
+	var value interface{}
+	decoder := jsonenc.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	err := decoder.Decode(&value)
+	return value, err
+}
+
+func maximum(x, y int) int {
+	if x < y {
+		return y
+	}
+	return x
+}
@@ -0,0 +1,126 @@
+package json
+
+import (
+	jsonenc "encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tolerance of numeric values. Two numbers are equal if their difference is within the absolute epsilon or within the relative epsilon of the biggest one.
+type Tolerance struct {
+	Absolute float64
+	Relative float64
+}
+
+// ParseTolerance from string. Relative tolerances are expressed as percentage (1%) and absolute ones as number (0.01)
+func ParseTolerance(tolerance string) (Tolerance, error) {
+
+	tolerance = strings.TrimSpace(tolerance)
+
+	if strings.HasSuffix(tolerance, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(tolerance, "%"), 64)
+		if err != nil || percentage < 0 {
+			return Tolerance{}, fmt.Errorf("Tolerance %s is not a valid percentage", tolerance)
+		}
+		return Tolerance{Relative: percentage / 100}, nil
+	}
+
+	epsilon, err := strconv.ParseFloat(tolerance, 64)
+	if err != nil || epsilon < 0 {
+		return Tolerance{}, fmt.Errorf("Tolerance %s is not a valid number", tolerance)
+	}
+	return Tolerance{Absolute: epsilon}, nil
+}
+
+// IsZero returns true if tolerance requires exact match
+func (tolerance Tolerance) IsZero() bool {
+	return tolerance.Absolute == 0 && tolerance.Relative == 0
+}
+
+func (tolerance Tolerance) accepts(a, b float64) bool {
+	difference := math.Abs(a - b)
+
+	if difference <= tolerance.Absolute {
+		return true
+	}
+
+	return difference <= tolerance.Relative*math.Max(math.Abs(a), math.Abs(b))
+}
+
+type toleranceRule struct {
+	pattern   pointerPattern
+	tolerance Tolerance
+}
+
+func compileToleranceRules(tolerances map[string]Tolerance) []toleranceRule {
+	// Pointers are sorted so in case of more than one pointer matching the same value, the chosen tolerance is always the same
+	var pointers []string
+	for pointer := range tolerances {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+
+	var rules []toleranceRule
+	for _, pointer := range pointers {
+		rules = append(rules, toleranceRule{pattern: compilePointerPattern(pointer), tolerance: tolerances[pointer]})
+	}
+	return rules
+}
+
+func findTolerance(rules []toleranceRule, defaultTolerance Tolerance, path []string) Tolerance {
+	for _, rule := range rules {
+		if rule.pattern.matches(path) {
+			return rule.tolerance
+		}
+	}
+	return defaultTolerance
+}
+
+// applyTolerances replaces numbers of other document by the primary ones when they are within tolerance, so they are considered equal.
+// Numbers out of tolerance are not modified so both values are reported as difference.
+func applyTolerances(primary, other interface{}, path []string, rules []toleranceRule, defaultTolerance Tolerance) interface{} {
+
+	switch otherValue := other.(type) {
+	case jsonenc.Number:
+		primaryValue, ok := primary.(jsonenc.Number)
+		if !ok {
+			return other
+		}
+
+		tolerance := findTolerance(rules, defaultTolerance, path)
+		if tolerance.IsZero() {
+			return other
+		}
+
+		a, errA := primaryValue.Float64()
+		b, errB := otherValue.Float64()
+		if errA == nil && errB == nil && tolerance.accepts(a, b) {
+			return primaryValue
+		}
+	case map[string]interface{}:
+		primaryValue, ok := primary.(map[string]interface{})
+		if !ok {
+			return other
+		}
+		for key, child := range otherValue {
+			if primaryChild, ok := primaryValue[key]; ok {
+				otherValue[key] = applyTolerances(primaryChild, child, appendToken(path, key), rules, defaultTolerance)
+			}
+		}
+	case []interface{}:
+		primaryValue, ok := primary.([]interface{})
+		if !ok {
+			return other
+		}
+		for i := range otherValue {
+			if i < len(primaryValue) {
+				otherValue[i] = applyTolerances(primaryValue[i], otherValue[i], appendToken(path, strconv.Itoa(i)), rules, defaultTolerance)
+			}
+		}
+	}
+
+	return other
+}
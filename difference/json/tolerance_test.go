@@ -0,0 +1,44 @@
+package json_test
+
+import (
+	"github.com/lordofthejars/diferencia/difference/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Numeric Tolerance", func() {
+
+	Describe("Parsing tolerances", func() {
+		Context("Valid tolerances", func() {
+			It("should parse absolute tolerance", func() {
+				tolerance, err := json.ParseTolerance("0.01")
+
+				Expect(err).Should(Succeed())
+				Expect(tolerance).Should(Equal(json.Tolerance{Absolute: 0.01}))
+			})
+
+			It("should parse relative tolerance", func() {
+				tolerance, err := json.ParseTolerance("5%")
+
+				Expect(err).Should(Succeed())
+				Expect(tolerance).Should(Equal(json.Tolerance{Relative: 0.05}))
+			})
+		})
+
+		Context("Invalid tolerances", func() {
+			It("should fail if it is not a number", func() {
+				_, err := json.ParseTolerance("a%")
+
+				Expect(err).Should(HaveOccurred())
+			})
+
+			It("should fail if it is negative", func() {
+				_, err := json.ParseTolerance("-0.1")
+
+				Expect(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+})
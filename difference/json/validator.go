@@ -0,0 +1,65 @@
+package json
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SchemaValidator validates documents against a JSON Schema document
+type SchemaValidator struct {
+	schema *gojsonschema.Schema
+}
+
+// NewSchemaValidator loads and compiles the JSON Schema document located at given path
+func NewSchemaValidator(path string) (*SchemaValidator, error) {
+
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://" + filepath.ToSlash(absolutePath)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchemaValidator{schema: schema}, nil
+}
+
+// Validate document against the schema and returns true if it is valid or false and the list of violations
+// in the form of `"pointer": description`
+func (validator *SchemaValidator) Validate(document []byte) (bool, string) {
+
+	result, err := validator.schema.Validate(gojsonschema.NewBytesLoader(document))
+	if err != nil {
+		return false, fmt.Sprintf(`"": %s`, err.Error())
+	}
+
+	var violations []string
+	for _, resultError := range result.Errors() {
+		violations = append(violations, fmt.Sprintf(`"%s": %s`, errorPointer(resultError), resultError.Description()))
+	}
+
+	return result.Valid(), strings.Join(violations, "\n")
+}
+
+// errorPointer transforms the context of the error ((root).a.0.b) to a JSON pointer (/a/0/b)
+func errorPointer(resultError gojsonschema.ResultError) string {
+
+	const separator = "\x00"
+
+	// First token is always the root element
+	path := strings.Split(resultError.Context().String(separator), separator)[1:]
+
+	// In case of missing properties, context is the parent object, so the property is appended
+	if resultError.Type() == "required" {
+		if property, ok := resultError.Details()["property"].(string); ok {
+			path = append(path, property)
+		}
+	}
+
+	return toPointer(path)
+}
@@ -0,0 +1,58 @@
+package json_test
+
+import (
+	"github.com/lordofthejars/diferencia/difference/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Json Schema Validation", func() {
+
+	Describe("Validate Json document against a schema", func() {
+		Context("With a valid document", func() {
+			It("should return that is valid", func() {
+				validator, err := json.NewSchemaValidator("test_fixtures/document-c-schema.json")
+				Expect(err).Should(Succeed())
+
+				document := loadFromFile("test_fixtures/document-c.json")
+
+				result, output := validator.Validate(document)
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+
+		Context("With an invalid document", func() {
+			It("should return the pointer of invalid type", func() {
+				validator, err := json.NewSchemaValidator("test_fixtures/document-c-schema.json")
+				Expect(err).Should(Succeed())
+
+				document := loadFromFile("test_fixtures/document-c-update-type.json")
+
+				result, output := validator.Validate(document)
+				Expect(result).To(Equal(false))
+				Expect(output).To(HavePrefix(`"/b/c": `))
+			})
+
+			It("should return the pointer of missing property", func() {
+				validator, err := json.NewSchemaValidator("test_fixtures/document-c-schema.json")
+				Expect(err).Should(Succeed())
+
+				document := loadFromFile("test_fixtures/document-c-simple.json")
+
+				result, output := validator.Validate(document)
+				Expect(result).To(Equal(false))
+				Expect(output).To(HavePrefix(`"/b": `))
+			})
+		})
+
+		Context("With an invalid schema location", func() {
+			It("should return an error", func() {
+				_, err := json.NewSchemaValidator("test_fixtures/not-found.json")
+				Expect(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+})
@@ -0,0 +1,56 @@
+package plain
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// DefaultCharset used when content type does not set any charset
+const DefaultCharset = "utf-8"
+
+// Charset returns the lower case name of the charset set in content type, or utf-8 if it is not set.
+// The name is returned as it is set, since canonical names of several charsets are the same (us-ascii and iso-8859-1 are windows-1252).
+func Charset(contentType string) (string, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || len(params["charset"]) == 0 {
+		return DefaultCharset, nil
+	}
+
+	charset := strings.ToLower(strings.TrimSpace(params["charset"]))
+	if _, err := htmlindex.Get(charset); err != nil {
+		return "", fmt.Errorf("Charset %s is not supported", params["charset"])
+	}
+
+	return charset, nil
+}
+
+// DecodeText returns the content encoded with charset as UTF-8 content
+func DecodeText(content []byte, charset string) ([]byte, error) {
+	if charset == DefaultCharset {
+		return content, nil
+	}
+
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("Charset %s is not supported", charset)
+	}
+
+	return encoding.NewDecoder().Bytes(content)
+}
+
+// EncodeText returns the UTF-8 content encoded with charset
+func EncodeText(content []byte, charset string) ([]byte, error) {
+	if charset == DefaultCharset {
+		return content, nil
+	}
+
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("Charset %s is not supported", charset)
+	}
+
+	return encoding.NewEncoder().Bytes(content)
+}
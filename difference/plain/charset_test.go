@@ -0,0 +1,61 @@
+package plain_test
+
+import (
+	"github.com/lordofthejars/diferencia/difference/plain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Charset", func() {
+
+	Describe("Getting charset from content type", func() {
+		It("should return lower case name of the charset", func() {
+			charset, err := plain.Charset("text/plain; charset=UTF-8")
+
+			Expect(err).Should(Succeed())
+			Expect(charset).Should(Equal("utf-8"))
+		})
+
+		It("should not replace the charset by the canonical name of its encoding", func() {
+			charset, err := plain.Charset("text/plain; charset=US-ASCII")
+
+			Expect(err).Should(Succeed())
+			Expect(charset).Should(Equal("us-ascii"))
+		})
+
+		It("should return utf-8 if charset is not set", func() {
+			charset, err := plain.Charset("text/plain")
+
+			Expect(err).Should(Succeed())
+			Expect(charset).Should(Equal(plain.DefaultCharset))
+		})
+
+		It("should fail if charset is not supported", func() {
+			_, err := plain.Charset("text/plain; charset=klingon")
+
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Decoding text", func() {
+		It("should decode ISO-8859-1 text as UTF-8 text", func() {
+			charset, _ := plain.Charset("text/plain; charset=ISO-8859-1")
+
+			text, err := plain.DecodeText([]byte{0x43, 0x72, 0xe8, 0x6d, 0x65}, charset)
+
+			Expect(err).Should(Succeed())
+			Expect(string(text)).Should(Equal("Crème"))
+		})
+	})
+
+	Describe("Encoding text", func() {
+		It("should encode UTF-8 text as ISO-8859-1 text", func() {
+			charset, _ := plain.Charset("text/plain; charset=ISO-8859-1")
+
+			content, err := plain.EncodeText([]byte("Crème"), charset)
+
+			Expect(err).Should(Succeed())
+			Expect(content).Should(Equal([]byte{0x43, 0x72, 0xe8, 0x6d, 0x65}))
+		})
+	})
+})
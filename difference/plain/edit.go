@@ -0,0 +1,192 @@
+package plain
+
+// edit of the shortest edit script with the position (starting at 1) of the element in original and candidate sequences
+type edit struct {
+	kind      byte
+	original  int
+	candidate int
+}
+
+// shortestEdit calculates the shortest edit script between an original sequence of n elements and a candidate sequence of m elements
+// using the linear space refinement of Myers algorithm, so memory only grows with the length of the sequences.
+// If more than maxDistance insertions and deletions are required, the script is not calculated and false is returned.
+// Deletions are placed before insertions in each change.
+func shortestEdit(n, m int, equal func(x, y int) bool, maxDistance int) ([]edit, bool) {
+	size := (n+m+1)/2 + 1
+	s := &myers{
+		equal:    equal,
+		forward:  make([]int, 2*size+1),
+		backward: make([]int, 2*size+1),
+		limit:    maxDistance,
+	}
+
+	if !s.compare(0, n, 0, m) {
+		return nil, false
+	}
+
+	return s.positions(), true
+}
+
+// myers calculates the edit script of a pair of subsequences finding their middle snake and then the edit script of the subsequences before and after it
+type myers struct {
+	equal             func(x, y int) bool
+	forward, backward []int
+	limit             int
+	limited           bool
+	kinds             []byte
+}
+
+// compare appends the kinds of edits between original[x0:x1] and candidate[y0:y1]. Only the whole sequences are bounded by the limit,
+// since the distance of every subsequence is lower than the distance of the whole sequences.
+func (s *myers) compare(x0, x1, y0, y1 int) bool {
+	for x0 < x1 && y0 < y1 && s.equal(x0, y0) {
+		s.kinds = append(s.kinds, equalLine)
+		x0++
+		y0++
+	}
+
+	suffix := 0
+	for x0 < x1 && y0 < y1 && s.equal(x1-1, y1-1) {
+		suffix++
+		x1--
+		y1--
+	}
+
+	limit := -1
+	if !s.limited {
+		limit = s.limit
+		s.limited = true
+	}
+
+	switch {
+	case x0 == x1 || y0 == y1:
+		if limit >= 0 && x1-x0+y1-y0 > limit {
+			return false
+		}
+		s.repeat(deletedLine, x1-x0)
+		s.repeat(insertedLine, y1-y0)
+	default:
+		startX, startY, endX, endY, found := s.middleSnake(x0, x1, y0, y1, limit)
+		if !found {
+			return false
+		}
+
+		s.compare(x0, startX, y0, startY)
+		s.repeat(equalLine, endX-startX)
+		s.compare(endX, x1, endY, y1)
+	}
+
+	s.repeat(equalLine, suffix)
+	return true
+}
+
+func (s *myers) repeat(kind byte, times int) {
+	for i := 0; i < times; i++ {
+		s.kinds = append(s.kinds, kind)
+	}
+}
+
+// middleSnake finds the snake in the middle of the shortest edit script between original[x0:x1] and candidate[y0:y1]
+// searching from the beginning and from the end at the same time. A negative limit means that the distance is not bounded.
+func (s *myers) middleSnake(x0, x1, y0, y1, limit int) (int, int, int, int, bool) {
+	n, m := x1-x0, y1-y0
+	delta := n - m
+	odd := delta%2 != 0
+	offset := (n+m+1)/2 + 1
+	maxD := (n + m + 1) / 2
+	if limit >= 0 && (limit+1)/2 < maxD {
+		maxD = (limit + 1) / 2
+	}
+
+	forward, backward := s.forward, s.backward
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+
+			for x < n && y < m && s.equal(x0+x, y0+y) {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// Paths overlap when the diagonal was reached by the previous backward step
+			if reverseK := delta - k; odd && reverseK >= -(d-1) && reverseK <= d-1 && x+backward[offset+reverseK] >= n {
+				if limit >= 0 && 2*d-1 > limit {
+					return 0, 0, 0, 0, false
+				}
+				return x0 + startX, y0 + startY, x0 + x, y0 + y, true
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+
+			for x < n && y < m && s.equal(x1-x-1, y1-y-1) {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if reverseK := delta - k; !odd && reverseK >= -d && reverseK <= d && x+forward[offset+reverseK] >= n {
+				if limit >= 0 && 2*d > limit {
+					return 0, 0, 0, 0, false
+				}
+				return x1 - x, y1 - y, x1 - startX, y1 - startY, true
+			}
+		}
+	}
+
+	return 0, 0, 0, 0, false
+}
+
+// positions sets the position of each edit, placing the deletions of each change before its insertions
+func (s *myers) positions() []edit {
+	edits := make([]edit, 0, len(s.kinds))
+	x, y := 0, 0
+
+	for i := 0; i < len(s.kinds); {
+		if s.kinds[i] == equalLine {
+			x++
+			y++
+			edits = append(edits, edit{kind: equalLine, original: x, candidate: y})
+			i++
+			continue
+		}
+
+		deleted, inserted := 0, 0
+		for ; i < len(s.kinds) && s.kinds[i] != equalLine; i++ {
+			if s.kinds[i] == deletedLine {
+				deleted++
+			} else {
+				inserted++
+			}
+		}
+
+		for j := 0; j < deleted; j++ {
+			x++
+			edits = append(edits, edit{kind: deletedLine, original: x, candidate: y})
+		}
+		for j := 0; j < inserted; j++ {
+			y++
+			edits = append(edits, edit{kind: insertedLine, original: x, candidate: y})
+		}
+	}
+
+	return edits
+}
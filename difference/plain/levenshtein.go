@@ -5,7 +5,7 @@ import (
 	"math"
 )
 
-// CalculateSimilarity between two strings
+// CalculateSimilarity between two UTF-8 strings. Similarity is calculated over characters and not bytes.
 func CalculateSimilarity(str1, str2 []byte) float64 {
 
 	if (str1 == nil) || (str2) == nil {
@@ -19,38 +19,157 @@ func CalculateSimilarity(str1, str2 []byte) float64 {
 		return 1
 	}
 
-	stepsToSame := Levenshtein(str1, str2)
+	runes1 := bytes.Runes(str1)
+	runes2 := bytes.Runes(str2)
+	length := maximum(len(runes1), len(runes2))
 
-	result := (1.0 - (float64(stepsToSame) / float64(maximum(len(str1), len(str2)))))
-	return math.Floor(result*100) / 100
+	stepsToSame, _ := levenshtein(runes1, runes2, length)
+
+	return similarity(stepsToSame, length)
 }
 
-// Levenshtein calculation
+// CalculateSimilarityAbove returns the same similarity as CalculateSimilarity and true if its percentage is greater than given percentage.
+// Computation stops as soon as the similarity cannot be greater than percentage, and in this case the returned similarity is the maximum one
+// both strings can have.
+func CalculateSimilarityAbove(str1, str2 []byte, percentage int) (float64, bool) {
+
+	if (len(str1) == 0) || (len(str2) == 0) || bytes.Equal(str1, str2) {
+		result := CalculateSimilarity(str1, str2)
+		return result, isAbove(result, percentage)
+	}
+
+	runes1 := bytes.Runes(str1)
+	runes2 := bytes.Runes(str2)
+	length := maximum(len(runes1), len(runes2))
+
+	allowedSteps := maximumSteps(length, percentage)
+	if allowedSteps < 0 {
+		return similarity(0, length), false
+	}
+
+	stepsToSame, within := levenshtein(runes1, runes2, allowedSteps)
+	if !within {
+		return similarity(allowedSteps+1, length), false
+	}
+
+	result := similarity(stepsToSame, length)
+	return result, isAbove(result, percentage)
+}
+
+// Levenshtein calculation between two UTF-8 strings. Each character counts as one step regardless of its size in bytes.
 func Levenshtein(str1, str2 []byte) int {
+	runes1 := bytes.Runes(str1)
+	runes2 := bytes.Runes(str2)
+
+	steps, _ := levenshtein(runes1, runes2, maximum(len(runes1), len(runes2)))
+	return steps
+}
+
+func similarity(steps, length int) float64 {
+	result := (1.0 - (float64(steps) / float64(length)))
+	return math.Floor(result*100) / 100
+}
+
+func isAbove(similarity float64, percentage int) bool {
+	return int(similarity*100) > percentage
+}
+
+// maximumSteps returns the maximum number of steps that strings of given length can be apart to have a similarity greater than percentage,
+// or -1 if not even equal strings have it
+func maximumSteps(length, percentage int) int {
+	steps := length * (100 - percentage) / 100
+
+	// Similarity is rounded, so the estimation is adjusted to get exactly the same result as comparing the similarity
+	for steps >= 0 && !isAbove(similarity(steps, length), percentage) {
+		steps--
+	}
+	for steps < length && isAbove(similarity(steps+1, length), percentage) {
+		steps++
+	}
+
+	return steps
+}
+
+// levenshtein calculates the distance using only two rows and only the cells of the band of width 2*max+1 around the diagonal,
+// since cells outside the band are always greater than max. It returns false as soon as the distance is greater than max.
+func levenshtein(str1, str2 []rune, max int) (int, bool) {
+
+	// Common prefix and suffix do not change the distance
+	for len(str1) > 0 && len(str2) > 0 && str1[0] == str2[0] {
+		str1, str2 = str1[1:], str2[1:]
+	}
+	for len(str1) > 0 && len(str2) > 0 && str1[len(str1)-1] == str2[len(str2)-1] {
+		str1, str2 = str1[:len(str1)-1], str2[:len(str2)-1]
+	}
+
+	// Rows are as long as the shortest string
+	if len(str1) < len(str2) {
+		str1, str2 = str2, str1
+	}
 
 	s1len := len(str1)
 	s2len := len(str2)
-	column := make([]int, len(str1)+1)
 
-	for y := 1; y <= s1len; y++ {
-		column[y] = y
+	if s1len-s2len > max {
+		return max + 1, false
 	}
-	for x := 1; x <= s2len; x++ {
-		column[0] = x
-		lastkey := x - 1
-		for y := 1; y <= s1len; y++ {
-			oldkey := column[y]
+
+	if s2len == 0 {
+		return s1len, true
+	}
+
+	outOfBand := max + 1
+	previous := make([]int, s2len+1)
+	current := make([]int, s2len+1)
+
+	for y := 0; y <= s2len; y++ {
+		previous[y] = y
+	}
+
+	for x := 1; x <= s1len; x++ {
+		from := maximum(1, x-max)
+		to := s2len
+		if x+max < to {
+			to = x + max
+		}
+
+		rowMinimum := outOfBand
+		if from == 1 {
+			current[0] = x
+			rowMinimum = x
+		} else {
+			current[from-1] = outOfBand
+		}
+
+		for y := from; y <= to; y++ {
 			var incr int
-			if str1[y-1] != str2[x-1] {
+			if str1[x-1] != str2[y-1] {
 				incr = 1
 			}
 
-			column[y] = minimum(column[y]+1, column[y-1]+1, lastkey+incr)
-			lastkey = oldkey
+			current[y] = minimum(previous[y]+1, current[y-1]+1, previous[y-1]+incr)
+			if current[y] < rowMinimum {
+				rowMinimum = current[y]
+			}
+		}
+
+		// Next row must not read stale values outside of the band
+		if to < s2len {
+			current[to+1] = outOfBand
+		}
+
+		if rowMinimum > max {
+			return outOfBand, false
 		}
+
+		previous, current = current, previous
+	}
+
+	if previous[s2len] > max {
+		return outOfBand, false
 	}
-	return column[s1len]
 
+	return previous[s2len], true
 }
 
 func maximum(x, y int) int {
@@ -1,6 +1,12 @@
 package plain_test
 
 import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
 	"github.com/lordofthejars/diferencia/difference/plain"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -69,7 +75,213 @@ var _ = Describe("Levenshtein Operation", func() {
 				Expect(result).Should(Equal(0.67))
 
 			})
+
+			It("should calculate diference over characters instead of bytes", func() {
+
+				// Given
+				str1 := []byte("Crème brûlée")
+				str2 := []byte("Creme brulee")
+
+				// When
+				result := plain.CalculateSimilarity(str1, str2)
+
+				// Then
+				Expect(plain.Levenshtein(str1, str2)).Should(Equal(3))
+				Expect(result).Should(Equal(0.75))
+
+			})
+		})
+	})
+
+	Describe("Calculating Percentage Factor with minimum percentage", func() {
+
+		Context("Calculate diference", func() {
+			It("should return same similarity as without minimum percentage if it is greater", func() {
+
+				// Given
+				str1 := []byte("recommendation v1 from '99634814-sf4cl':")
+				str2 := []byte("recommendation v2 from '7544fgh6-uftj7':")
+
+				for _, percentage := range []int{0, 50, 66} {
+					// When
+					result, above := plain.CalculateSimilarityAbove(str1, str2, percentage)
+
+					// Then
+					Expect(result).Should(Equal(0.67))
+					Expect(above).Should(Equal(true))
+				}
+
+			})
+
+			It("should stop when similarity cannot be greater than minimum percentage", func() {
+
+				// Given
+				str1 := []byte("Orange")
+				str2 := []byte("Apple")
+
+				// When
+				result, above := plain.CalculateSimilarityAbove(str1, str2, 50)
+
+				// Then
+				Expect(above).Should(Equal(false))
+				Expect(result).Should(BeNumerically("<=", 0.5))
+				Expect(result).Should(BeNumerically(">=", plain.CalculateSimilarity(str1, str2)))
+
+			})
+
+			It("should match similarity of random texts calculated with the full matrix", func() {
+
+				random := rand.New(rand.NewSource(42))
+
+				for i := 0; i < 500; i++ {
+					// Given
+					str1 := []byte(randomText(random, random.Intn(40)+1, "abcé"))
+					str2 := []byte(randomText(random, random.Intn(40)+1, "abcé"))
+					if i%2 == 0 {
+						// Similar texts exercise the band around the diagonal
+						str2 = mutateText(random, str1, random.Intn(5), "abcé")
+					}
+					percentage := random.Intn(100)
+
+					// When
+					result, above := plain.CalculateSimilarityAbove(str1, str2, percentage)
+					expected := referenceSimilarity(str1, str2)
+
+					// Then
+					Expect(plain.Levenshtein(str1, str2)).Should(Equal(referenceLevenshtein(bytes.Runes(str1), bytes.Runes(str2))))
+					Expect(plain.CalculateSimilarity(str1, str2)).Should(Equal(expected))
+					Expect(above).Should(Equal(int(expected*100) > percentage))
+					if above {
+						Expect(result).Should(Equal(expected))
+					} else {
+						Expect(result).Should(BeNumerically(">=", expected))
+					}
+				}
+
+			})
 		})
 	})
 
 })
+
+func randomText(random *rand.Rand, length int, alphabet string) string {
+	letters := []rune(alphabet)
+	text := make([]rune, length)
+	for i := range text {
+		text[i] = letters[random.Intn(len(letters))]
+	}
+	return string(text)
+}
+
+// mutateText replaces, inserts or deletes characters of text
+func mutateText(random *rand.Rand, text []byte, changes int, alphabet string) []byte {
+	letters := []rune(alphabet)
+	runes := bytes.Runes(text)
+	for i := 0; i < changes; i++ {
+		position := random.Intn(len(runes) + 1)
+		switch random.Intn(3) {
+		case 0:
+			if position < len(runes) {
+				runes[position] = letters[random.Intn(len(letters))]
+			}
+		case 1:
+			runes = append(runes[:position], append([]rune{letters[random.Intn(len(letters))]}, runes[position:]...)...)
+		case 2:
+			if position < len(runes) {
+				runes = append(runes[:position], runes[position+1:]...)
+			}
+		}
+	}
+	return []byte(string(runes))
+}
+
+// referenceLevenshtein is the previous implementation, which calculates every cell of the matrix, used to verify the banded one
+func referenceLevenshtein(str1, str2 []rune) int {
+
+	s1len := len(str1)
+	s2len := len(str2)
+	column := make([]int, len(str1)+1)
+
+	for y := 1; y <= s1len; y++ {
+		column[y] = y
+	}
+	for x := 1; x <= s2len; x++ {
+		column[0] = x
+		lastkey := x - 1
+		for y := 1; y <= s1len; y++ {
+			oldkey := column[y]
+			incr := 1
+			if str1[y-1] == str2[x-1] {
+				incr = 0
+			}
+
+			column[y] = referenceMinimum(column[y]+1, column[y-1]+1, lastkey+incr)
+			lastkey = oldkey
+		}
+	}
+	return column[s1len]
+
+}
+
+func referenceMinimum(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+// referenceSimilarity is the previous similarity calculation using referenceLevenshtein
+func referenceSimilarity(str1, str2 []byte) float64 {
+	if len(str1) == 0 || len(str2) == 0 {
+		return 0
+	}
+
+	runes1 := bytes.Runes(str1)
+	runes2 := bytes.Runes(str2)
+	length := len(runes1)
+	if len(runes2) > length {
+		length = len(runes2)
+	}
+
+	result := 1.0 - (float64(referenceLevenshtein(runes1, runes2)) / float64(length))
+	return math.Floor(result*100) / 100
+}
+
+// largeTexts returns two texts of about 16KB where one character of every 50 is different
+func largeTexts() ([]byte, []byte) {
+	paragraph := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt. "
+	primary := []byte(strings.Repeat(paragraph, 180))
+	candidate := append([]byte(nil), primary...)
+	for i := 0; i < len(candidate); i += 50 {
+		candidate[i] = '#'
+	}
+	return primary, candidate
+}
+
+// BenchmarkReferenceLevenshtein measures the previous implementation to compare it with BenchmarkLevenshtein and BenchmarkLevenshteinWithMinimumPercentage
+func BenchmarkReferenceLevenshtein(b *testing.B) {
+	primary, candidate := largeTexts()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceSimilarity(primary, candidate)
+	}
+}
+
+func BenchmarkLevenshtein(b *testing.B) {
+	primary, candidate := largeTexts()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plain.CalculateSimilarity(primary, candidate)
+	}
+}
+
+func BenchmarkLevenshteinWithMinimumPercentage(b *testing.B) {
+	primary, candidate := largeTexts()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plain.CalculateSimilarityAbove(primary, candidate, 99)
+	}
+}
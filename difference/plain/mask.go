@@ -0,0 +1,58 @@
+package plain
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// MaskPlaceholder replaces the texts matching a pattern, where %d is the position of the pattern starting at 1.
+// Each pattern has its own placeholder so a text matching one pattern is not equal to a text matching another one.
+const MaskPlaceholder = "<masked:%d>"
+
+type match struct {
+	start   int
+	end     int
+	pattern int
+}
+
+// Mask replaces the texts matching any of the patterns with the placeholder of the pattern.
+// If matches of different patterns overlap, the leftmost one is replaced, and the first pattern in case of starting at the same position.
+func Mask(text []byte, patterns []*regexp.Regexp) []byte {
+
+	var matches []match
+	for i, pattern := range patterns {
+		for _, location := range pattern.FindAllIndex(text, -1) {
+			// Empty matches would insert placeholders everywhere
+			if location[1] > location[0] {
+				matches = append(matches, match{start: location[0], end: location[1], pattern: i})
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return text
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	var b bytes.Buffer
+	position := 0
+
+	for _, m := range matches {
+		if m.start < position {
+			continue
+		}
+
+		b.Write(text[position:m.start])
+		fmt.Fprintf(&b, MaskPlaceholder, m.pattern+1)
+		position = m.end
+	}
+
+	b.Write(text[position:])
+
+	return b.Bytes()
+}
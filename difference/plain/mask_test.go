@@ -0,0 +1,38 @@
+package plain_test
+
+import (
+	"regexp"
+
+	"github.com/lordofthejars/diferencia/difference/plain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mask", func() {
+
+	uuid := regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	timestamp := regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`)
+	hex := regexp.MustCompile(`[0-9a-f]{16,}`)
+
+	It("should replace every match with the placeholder of its pattern", func() {
+		text := []byte("Request 0d1e2f3a-4b5c-6d7e-8f90-a1b2c3d4e5f6 at 2018-10-01T10:15:00Z and 2018-10-01T10:15:01Z")
+
+		masked := plain.Mask(text, []*regexp.Regexp{uuid, timestamp})
+
+		Expect(string(masked)).Should(Equal("Request <masked:1> at <masked:2> and <masked:2>"))
+	})
+
+	It("should replace overlapping matches only once", func() {
+		text := []byte("session 0d1e2f3a-4b5c-6d7e-8f90-a1b2c3d4e5f6 token 0123456789abcdef0123")
+
+		masked := plain.Mask(text, []*regexp.Regexp{hex, uuid})
+
+		Expect(string(masked)).Should(Equal("session <masked:2> token <masked:1>"))
+	})
+
+	It("should return text untouched if there are no patterns", func() {
+		text := []byte("Request 0d1e2f3a-4b5c-6d7e-8f90-a1b2c3d4e5f6")
+
+		Expect(plain.Mask(text, nil)).Should(Equal(text))
+	})
+})
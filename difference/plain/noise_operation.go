@@ -1,9 +1,27 @@
 package plain
 
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MinimumAnchorLength is the minimum number of characters that unchanged text between two noise regions must have to keep them apart.
+// Shorter texts, like the colons of a timestamp, are considered part of the noise.
+const MinimumAnchorLength = 3
+
+// MaxNoiseCharacters is the maximum number of characters of changed lines in primary and secondary that are aligned to detect noise
+const MaxNoiseCharacters = 20000
+
+// MaxNoiseDistance is the maximum number of deleted and inserted characters of changed lines in primary and secondary to detect noise
+const MaxNoiseDistance = 2000
+
 // NoiseOperation struct
 type NoiseOperation struct {
-	index int
-	noise bool
+	// anchors are the texts of primary that are equal in secondary, in order. Noise is located between each pair of anchors.
+	anchors []string
+	noise   bool
 }
 
 // ContainsNoise method
@@ -11,45 +29,226 @@ func (nd NoiseOperation) ContainsNoise() bool {
 	return nd.noise
 }
 
-// Detect Noise between documents
-func (nd *NoiseOperation) Detect(primary, secondary []byte) {
+// Detect Noise between documents. Documents are aligned line by line and then character by character inside changed lines,
+// so every region that is different between primary and secondary is detected as noise.
+// An error is returned if documents are too large or too different to be aligned, so the work done is bounded.
+func (nd *NoiseOperation) Detect(primary, secondary []byte) error {
+
+	nd.anchors = nil
+	nd.noise = !bytes.Equal(primary, secondary)
+
+	if !nd.ContainsNoise() {
+		return nil
+	}
+
+	regions := noiseRegions{}
 
-	for index := range primary {
-		if index >= len(secondary) {
-			nd.index = index
-			nd.noise = true
-			break
+	primaryLines := splitLinesWithEnd(primary)
+	secondaryLines := splitLinesWithEnd(secondary)
+
+	lines, ok := diffLines(primaryLines, secondaryLines, MaxDiffLines, MaxDiffDistance)
+	if !ok {
+		return fmt.Errorf("Noise cannot be detected in texts with %d and %d lines, they are too large or have more than %d different lines", len(primaryLines), len(secondaryLines), MaxDiffDistance)
+	}
+
+	var deleted, inserted strings.Builder
+	flush := func() error {
+		if deleted.Len() > 0 || inserted.Len() > 0 {
+			if err := regions.changed(deleted.String(), inserted.String()); err != nil {
+				return err
+			}
+			deleted.Reset()
+			inserted.Reset()
 		}
+		return nil
+	}
 
-		if primary[index] != secondary[index] {
-			nd.index = index
-			nd.noise = true
-			break
+	for _, l := range lines {
+		switch l.kind {
+		case equalLine:
+			if err := flush(); err != nil {
+				return err
+			}
+			regions.equal(l.text)
+		case deletedLine:
+			deleted.WriteString(l.text)
+		case insertedLine:
+			inserted.WriteString(l.text)
 		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	nd.anchors = regions.anchors()
+	return nil
+
+}
 
+// Remove noise from primary and candidate documents.
+// Noise is located in each document between the texts that were equal in primary and secondary.
+// If one of these texts cannot be found in the document, the rest of the document is not touched so it is still compared.
+func (nd *NoiseOperation) Remove(primary, candidate []byte) ([]byte, []byte) {
+
+	// Primary and secondary have nothing in common, to avoid removing everything, we just return the original ones
+	if !nd.ContainsNoise() || len(strings.Join(nd.anchors, "")) == 0 {
+		return primary, candidate
 	}
 
-	if !nd.ContainsNoise() {
-		if len(secondary) > len(primary) {
-			nd.index = len(primary) - 1
-			nd.noise = true
+	return nd.mask(primary), nd.mask(candidate)
+
+}
+
+func (nd *NoiseOperation) mask(document []byte) []byte {
+
+	content := string(document)
+	first := nd.anchors[0]
+
+	if !strings.HasPrefix(content, first) {
+		return document
+	}
+
+	var b bytes.Buffer
+	b.WriteString(first)
+	position := len(first)
+	last := len(nd.anchors) - 1
+
+	for i := 1; i <= last; i++ {
+		anchor := nd.anchors[i]
+		index := -1
+
+		// Last anchor must be at the end of the document, an empty one means that the noise is at the end
+		if i == last {
+			if strings.HasSuffix(content[position:], anchor) {
+				index = len(content) - len(anchor)
+			}
+		} else if found := strings.Index(content[position:], anchor); found >= 0 {
+			index = position + found
+		}
+
+		if index < 0 {
+			b.WriteString(content[position:])
+			return b.Bytes()
 		}
+
+		b.WriteString(anchor)
+		position = index + len(anchor)
 	}
 
+	return b.Bytes()
+
 }
 
-// Remove noise from primary and candidate documents
-func (nd *NoiseOperation) Remove(primary, candidate []byte) ([]byte, []byte) {
+// noiseRegions builds the anchors of a document from the equal and changed texts between primary and secondary
+type noiseRegions struct {
+	texts   []string
+	current strings.Builder
+	noisy   bool
+}
 
-	// It is different from first char, to avoid false substring, we just return the original one
-	if nd.ContainsNoise() {
-		if nd.index == 0 {
-			return primary, candidate
+func (r *noiseRegions) equal(text string) {
+	r.noisy = false
+	r.current.WriteString(text)
+}
+
+func (r *noiseRegions) noise() {
+	if !r.noisy {
+		r.texts = append(r.texts, r.current.String())
+		r.current.Reset()
+		r.noisy = true
+	}
+}
+
+// changed aligns the changed lines character by character. Texts that are not valid UTF-8, like undecoded texts, are aligned byte by byte,
+// since every invalid sequence would be the same replacement character.
+// Only the characters between the common prefix and suffix are bounded by MaxNoiseCharacters and MaxNoiseDistance.
+func (r *noiseRegions) changed(primary, secondary string) error {
+	byBytes := !utf8.ValidString(primary) || !utf8.ValidString(secondary)
+	primaryCharacters := characters(primary, byBytes)
+	secondaryCharacters := characters(secondary, byBytes)
+
+	prefix := 0
+	for prefix < len(primaryCharacters) && prefix < len(secondaryCharacters) && primaryCharacters[prefix] == secondaryCharacters[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(primaryCharacters)-prefix && suffix < len(secondaryCharacters)-prefix &&
+		primaryCharacters[len(primaryCharacters)-suffix-1] == secondaryCharacters[len(secondaryCharacters)-suffix-1] {
+		suffix++
+	}
+
+	primaryChanged := primaryCharacters[prefix : len(primaryCharacters)-suffix]
+	secondaryChanged := secondaryCharacters[prefix : len(secondaryCharacters)-suffix]
+
+	if len(primaryChanged)+len(secondaryChanged) > MaxNoiseCharacters {
+		return fmt.Errorf("Noise cannot be detected in changed lines with %d and %d different characters, more than %d characters in total", len(primaryChanged), len(secondaryChanged), MaxNoiseCharacters)
+	}
+
+	edits, ok := shortestEdit(len(primaryChanged), len(secondaryChanged), func(x, y int) bool {
+		return primaryChanged[x] == secondaryChanged[y]
+	}, MaxNoiseDistance)
+
+	if !ok {
+		return fmt.Errorf("Noise cannot be detected in changed lines with more than %d different characters", MaxNoiseDistance)
+	}
+
+	if prefix > 0 {
+		r.equal(strings.Join(primaryCharacters[:prefix], ""))
+	}
+
+	for _, e := range edits {
+		if e.kind == equalLine {
+			r.equal(primaryChanged[e.original-1])
 		} else {
-			return primary[0:nd.index], candidate[0:nd.index]
+			r.noise()
 		}
 	}
 
-	return primary, candidate
+	if suffix > 0 {
+		r.equal(strings.Join(primaryCharacters[len(primaryCharacters)-suffix:], ""))
+	}
+
+	return nil
+}
 
+// characters splits text in runes or in bytes
+func characters(text string, byBytes bool) []string {
+	var characters []string
+
+	if byBytes {
+		for i := 0; i < len(text); i++ {
+			characters = append(characters, text[i:i+1])
+		}
+		return characters
+	}
+
+	for _, character := range text {
+		characters = append(characters, string(character))
+	}
+	return characters
+}
+
+// anchors returns the equal texts, joining the noise regions that are separated by less than MinimumAnchorLength characters
+func (r *noiseRegions) anchors() []string {
+	texts := append(r.texts, r.current.String())
+	last := len(texts) - 1
+
+	anchors := []string{texts[0]}
+	for _, text := range texts[1:last] {
+		if utf8.RuneCountInString(text) >= MinimumAnchorLength {
+			anchors = append(anchors, text)
+		}
+	}
+
+	return append(anchors, texts[last])
+}
+
+// splitLinesWithEnd splits text in lines keeping the line break, so joining them returns the same text
+func splitLinesWithEnd(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		return lines[:len(lines)-1]
+	}
+	return lines
 }
@@ -1,6 +1,9 @@
 package plain_test
 
 import (
+	"math/rand"
+	"strings"
+
 	"github.com/lordofthejars/diferencia/difference/plain"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -61,6 +64,57 @@ var _ = Describe("Noise Operation", func() {
 
 			})
 
+			It("should detect noise in a long line in linear memory", func() {
+
+				primary := []byte(strings.Repeat("abcdefgh", 1000) + "2018-06-01T10:00:00Z" + strings.Repeat("abcdefgh", 1000))
+				secondary := []byte(strings.Repeat("abcdefgh", 1000) + "2019-07-02T11:11:11Z" + strings.Repeat("abcdefgh", 1000))
+
+				noiseOperation := plain.NoiseOperation{}
+
+				var err error
+				allocated := allocatedBytes(func() {
+					err = noiseOperation.Detect(primary, secondary)
+				})
+
+				Expect(err).Should(Succeed())
+				Expect(noiseOperation.ContainsNoise()).Should(BeTrue())
+				Expect(allocated).Should(BeNumerically("<", 10*1024*1024))
+
+			})
+
+		})
+
+		Context("Too different request", func() {
+			It("should return an error if changed lines have too many different characters", func() {
+
+				random := rand.New(rand.NewSource(3))
+				primary := []byte(randomText(random, 8000, "abcdefgh"))
+				secondary := []byte(randomText(random, 8000, "abcdefgh"))
+
+				noiseOperation := plain.NoiseOperation{}
+
+				var err error
+				allocated := allocatedBytes(func() {
+					err = noiseOperation.Detect(primary, secondary)
+				})
+
+				Expect(err).Should(HaveOccurred())
+				Expect(allocated).Should(BeNumerically("<", 10*1024*1024))
+
+			})
+
+			It("should return an error if changed lines are too long", func() {
+
+				primary := []byte(strings.Repeat("a", plain.MaxNoiseCharacters))
+				secondary := []byte(strings.Repeat("b", 10))
+
+				noiseOperation := plain.NoiseOperation{}
+
+				err := noiseOperation.Detect(primary, secondary)
+
+				Expect(err).Should(HaveOccurred())
+
+			})
 		})
 	})
 	Describe("Removing Noise from Documents", func() {
@@ -94,6 +148,81 @@ var _ = Describe("Noise Operation", func() {
 				Expect(newPrimary).Should(Equal([]byte("aaa")))
 				Expect(newCandidate).Should(Equal([]byte("aaa")))
 
+			})
+			It("should remove every region with noise", func() {
+
+				primary := []byte("Order 1234 created at 10:15:01 by alice")
+				secondary := []byte("Order 5678 created at 10:16:59 by alice")
+				candidate := []byte("Order 9012 created at 10:17:42 by alice")
+
+				noiseOperation := plain.NoiseOperation{}
+
+				noiseOperation.Detect(primary, secondary)
+				newPrimary, newCandidate := noiseOperation.Remove(primary, candidate)
+
+				Expect(string(newPrimary)).Should(Equal("Order  created at 10:1 by alice"))
+				Expect(string(newCandidate)).Should(Equal(string(newPrimary)))
+
+			})
+			It("should keep the candidate text that cannot be aligned with primary", func() {
+
+				primary := []byte("Order 1234 created by alice")
+				secondary := []byte("Order 5678 created by alice")
+				candidate := []byte("Order 9012 created by bob")
+
+				noiseOperation := plain.NoiseOperation{}
+
+				noiseOperation.Detect(primary, secondary)
+				newPrimary, newCandidate := noiseOperation.Remove(primary, candidate)
+
+				Expect(string(newPrimary)).Should(Equal("Order  created by alice"))
+				Expect(string(newCandidate)).Should(Equal("Order 9012 created by bob"))
+
+			})
+			It("should remove noise from different lines", func() {
+
+				primary := []byte("id: a1\nname: Alex\ndate: 2018-01-01\nstatus: ok\n")
+				secondary := []byte("id: b2\nname: Alex\ndate: 2018-02-03\nstatus: ok\n")
+				candidate := []byte("id: c3\nname: Alex\ndate: 2018-03-05\nstatus: ok\n")
+
+				noiseOperation := plain.NoiseOperation{}
+
+				noiseOperation.Detect(primary, secondary)
+				newPrimary, newCandidate := noiseOperation.Remove(primary, candidate)
+
+				Expect(string(newPrimary)).Should(Equal("id: \nname: Alex\ndate: 2018-0\nstatus: ok\n"))
+				Expect(string(newCandidate)).Should(Equal(string(newPrimary)))
+
+			})
+			It("should remove noise from the beginning of the documents", func() {
+
+				primary := []byte("1234 items")
+				secondary := []byte("5678 items")
+				candidate := []byte("90 items")
+
+				noiseOperation := plain.NoiseOperation{}
+
+				noiseOperation.Detect(primary, secondary)
+				newPrimary, newCandidate := noiseOperation.Remove(primary, candidate)
+
+				Expect(string(newPrimary)).Should(Equal(" items"))
+				Expect(string(newCandidate)).Should(Equal(" items"))
+
+			})
+			It("should remove noise from texts that are not valid UTF-8", func() {
+
+				primary := []byte("Cr\xe8me 1234\n")
+				secondary := []byte("Cr\xe8me 5678\n")
+				candidate := []byte("Cr\xe8me 9999\n")
+
+				noiseOperation := plain.NoiseOperation{}
+
+				noiseOperation.Detect(primary, secondary)
+				newPrimary, newCandidate := noiseOperation.Remove(primary, candidate)
+
+				Expect(newPrimary).Should(Equal([]byte("Cr\xe8me \n")))
+				Expect(newCandidate).Should(Equal(newPrimary))
+
 			})
 			It("should return untouched strings if they are totally different", func() {
 				primary := []byte("abcd")
@@ -0,0 +1,198 @@
+package plain
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// LevenshteinSimilarity compares texts by the number of characters to change
+	LevenshteinSimilarity = "Levenshtein"
+	// WordLevenshteinSimilarity compares texts by the number of words to change
+	WordLevenshteinSimilarity = "WordLevenshtein"
+	// JaccardSimilarity compares texts by the shingles of words they have in common
+	JaccardSimilarity = "Jaccard"
+	// CosineSimilarity compares texts by the cosine of their term frequency vectors
+	CosineSimilarity = "Cosine"
+	// LcsSimilarity compares texts by the ratio of their longest common subsequence of words
+	LcsSimilarity = "Lcs"
+)
+
+// ShingleSize is the number of consecutive words of each shingle in Jaccard similarity
+const ShingleSize = 3
+
+// Similarity between two texts from 0 (completely different) to 1 (equal)
+type Similarity func(str1, str2 []byte) float64
+
+// NewSimilarity returns the similarity algorithm with given name
+func NewSimilarity(name string) (Similarity, error) {
+	switch name {
+	case LevenshteinSimilarity:
+		return CalculateSimilarity, nil
+	case WordLevenshteinSimilarity:
+		return WordLevenshtein, nil
+	case JaccardSimilarity:
+		return Jaccard, nil
+	case CosineSimilarity:
+		return Cosine, nil
+	case LcsSimilarity:
+		return LcsRatio, nil
+	}
+
+	return nil, fmt.Errorf("Cannot find %s text similarity", name)
+}
+
+// WordLevenshtein similarity between two texts where each word counts as one step
+func WordLevenshtein(str1, str2 []byte) float64 {
+	return tokenSimilarity(str1, str2, func(words1, words2 []string) float64 {
+		return CalculateSimilarity(wordSymbols(words1, words2))
+	})
+}
+
+// WordLevenshteinAbove returns the same similarity as WordLevenshtein and true if its percentage is greater than given percentage.
+// Like CalculateSimilarityAbove, computation stops as soon as the similarity cannot be greater than percentage.
+func WordLevenshteinAbove(str1, str2 []byte, percentage int) (float64, bool) {
+	words1 := words(str1)
+	words2 := words(str2)
+
+	if bytes.Equal(str1, str2) || len(words1) == 0 || len(words2) == 0 {
+		result := WordLevenshtein(str1, str2)
+		return result, isAbove(result, percentage)
+	}
+
+	symbols1, symbols2 := wordSymbols(words1, words2)
+	return CalculateSimilarityAbove(symbols1, symbols2, percentage)
+}
+
+// Jaccard similarity between the sets of shingles of both texts
+func Jaccard(str1, str2 []byte) float64 {
+	return tokenSimilarity(str1, str2, func(words1, words2 []string) float64 {
+		shingles1 := shingles(words1)
+		shingles2 := shingles(words2)
+
+		intersection := 0
+		for shingle := range shingles1 {
+			if shingles2[shingle] {
+				intersection++
+			}
+		}
+
+		return float64(intersection) / float64(len(shingles1)+len(shingles2)-intersection)
+	})
+}
+
+// Cosine similarity between the term frequency vectors of both texts
+func Cosine(str1, str2 []byte) float64 {
+	return tokenSimilarity(str1, str2, func(words1, words2 []string) float64 {
+		frequencies1 := frequencies(words1)
+		frequencies2 := frequencies(words2)
+
+		var product, norm1, norm2 float64
+		for word, frequency := range frequencies1 {
+			product += frequency * frequencies2[word]
+			norm1 += frequency * frequency
+		}
+		for _, frequency := range frequencies2 {
+			norm2 += frequency * frequency
+		}
+
+		return product / (math.Sqrt(norm1) * math.Sqrt(norm2))
+	})
+}
+
+// LcsRatio returns two times the length of the longest common subsequence of words divided by the number of words of both texts
+func LcsRatio(str1, str2 []byte) float64 {
+	return tokenSimilarity(str1, str2, func(words1, words2 []string) float64 {
+		previous := make([]int, len(words2)+1)
+		current := make([]int, len(words2)+1)
+
+		for i := 1; i <= len(words1); i++ {
+			for j := 1; j <= len(words2); j++ {
+				if words1[i-1] == words2[j-1] {
+					current[j] = previous[j-1] + 1
+				} else {
+					current[j] = maximum(previous[j], current[j-1])
+				}
+			}
+			previous, current = current, previous
+		}
+
+		return 2.0 * float64(previous[len(words2)]) / float64(len(words1)+len(words2))
+	})
+}
+
+// tokenSimilarity splits texts in words and calculates the similarity rounded down to two decimals.
+// Equal texts are completely similar and texts without words are completely different to any other text.
+func tokenSimilarity(str1, str2 []byte, similarity func(words1, words2 []string) float64) float64 {
+	if bytes.Equal(str1, str2) {
+		return 1
+	}
+
+	words1 := words(str1)
+	words2 := words(str2)
+
+	if len(words1) == 0 || len(words2) == 0 {
+		return 0
+	}
+
+	// Small delta avoids rounding down values like 0.9999999 because of floating point errors
+	return math.Floor(similarity(words1, words2)*100+1e-9) / 100
+}
+
+func words(text []byte) []string {
+	return strings.FieldsFunc(string(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// shingles returns the set of sequences of ShingleSize consecutive words. Texts with less words have only one shingle.
+func shingles(words []string) map[string]bool {
+	set := make(map[string]bool)
+
+	if len(words) < ShingleSize {
+		set[strings.Join(words, " ")] = true
+		return set
+	}
+
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		set[strings.Join(words[i:i+ShingleSize], " ")] = true
+	}
+
+	return set
+}
+
+func frequencies(words []string) map[string]float64 {
+	frequencies := make(map[string]float64)
+	for _, word := range words {
+		frequencies[word]++
+	}
+	return frequencies
+}
+
+// wordSymbols encodes each distinct word of both texts as a different character,
+// so the distance between words is calculated with the Levenshtein distance of characters
+func wordSymbols(words1, words2 []string) ([]byte, []byte) {
+	symbols := make(map[string]rune)
+
+	encode := func(words []string) []byte {
+		var buffer bytes.Buffer
+		for _, word := range words {
+			symbol, ok := symbols[word]
+			if !ok {
+				symbol = rune(len(symbols))
+				// Surrogates (U+D800 to U+DFFF) cannot be encoded as UTF-8
+				if symbol >= 0xD800 {
+					symbol += 0x800
+				}
+				symbols[word] = symbol
+			}
+			buffer.WriteRune(symbol)
+		}
+		return buffer.Bytes()
+	}
+
+	return encode(words1), encode(words2)
+}
@@ -0,0 +1,66 @@
+package plain_test
+
+import (
+	"github.com/lordofthejars/diferencia/difference/plain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Text Similarity", func() {
+
+	primary := []byte("the quick brown fox jumps over the lazy dog")
+	candidate := []byte("the quick red fox jumps over the lazy dog")
+
+	Describe("Getting similarity algorithm", func() {
+		It("should return algorithm by name", func() {
+			similarity, err := plain.NewSimilarity(plain.JaccardSimilarity)
+
+			Expect(err).Should(Succeed())
+			Expect(similarity(primary, primary)).Should(Equal(1.0))
+		})
+
+		It("should fail if algorithm does not exist", func() {
+			_, err := plain.NewSimilarity("Hamming")
+
+			Expect(err).Should(MatchError("Cannot find Hamming text similarity"))
+		})
+	})
+
+	Describe("Calculating similarity", func() {
+		It("should calculate word levenshtein similarity", func() {
+			Expect(plain.WordLevenshtein(primary, candidate)).Should(Equal(0.88))
+		})
+
+		It("should stop word levenshtein similarity when it cannot be above percentage", func() {
+			similarity, above := plain.WordLevenshteinAbove(primary, candidate, 80)
+			Expect(similarity).Should(Equal(0.88))
+			Expect(above).Should(BeTrue())
+
+			similarity, above = plain.WordLevenshteinAbove(primary, candidate, 90)
+			Expect(similarity).Should(BeNumerically("<=", 0.9))
+			Expect(above).Should(BeFalse())
+		})
+
+		It("should calculate jaccard similarity over shingles", func() {
+			// 4 shared shingles of 7 + 7 - 4
+			Expect(plain.Jaccard(primary, candidate)).Should(Equal(0.4))
+		})
+
+		It("should calculate cosine similarity over term frequencies", func() {
+			// 10 / (sqrt(11) * sqrt(11)) since "the" appears twice
+			Expect(plain.Cosine(primary, candidate)).Should(Equal(0.9))
+		})
+
+		It("should calculate longest common subsequence ratio", func() {
+			Expect(plain.LcsRatio(primary, candidate)).Should(Equal(0.88))
+		})
+
+		It("should ignore punctuation and whitespaces", func() {
+			Expect(plain.Cosine([]byte("Hello,   world!"), []byte("Hello world"))).Should(Equal(1.0))
+		})
+
+		It("should return 0 if one of the texts has no words", func() {
+			Expect(plain.LcsRatio([]byte("..."), candidate)).Should(Equal(0.0))
+		})
+	})
+})
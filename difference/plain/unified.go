@@ -0,0 +1,167 @@
+package plain
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DefaultContextLines is the number of unchanged lines shown around each change
+const DefaultContextLines = 3
+
+// MaxDiffLines is the maximum number of lines of both texts to calculate their differences
+const MaxDiffLines = 100000
+
+// MaxDiffDistance is the maximum number of deleted and inserted lines to calculate the differences between two texts
+const MaxDiffDistance = 2000
+
+const (
+	equalLine    = ' '
+	deletedLine  = '-'
+	insertedLine = '+'
+)
+
+// line of the diff with the position in original and candidate documents
+type line struct {
+	kind      byte
+	text      string
+	original  int
+	candidate int
+}
+
+// UnifiedDiff returns the differences between original and candidate texts in unified diff format, or empty if they are equal.
+// Each change is shown surrounded by contextLines unchanged lines.
+// Texts with more than MaxDiffLines lines or more than MaxDiffDistance changed lines are only reported as different, since calculating their differences is too expensive.
+func UnifiedDiff(candidate, original []byte, contextLines int) string {
+
+	if bytes.Equal(candidate, original) {
+		return ""
+	}
+
+	originalLines, candidateLines := splitLines(original), splitLines(candidate)
+	lines, ok := diffLines(originalLines, candidateLines, MaxDiffLines, MaxDiffDistance)
+
+	if !ok {
+		return fmt.Sprintf("Texts are different (%d lines in primary, %d lines in candidate) but they are too large or too different to show their differences", len(originalLines), len(candidateLines))
+	}
+
+	var b bytes.Buffer
+	b.WriteString("--- primary\n")
+	b.WriteString("+++ candidate\n")
+
+	for _, hunk := range hunks(lines, contextLines) {
+		writeHunk(&b, hunk)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func splitLines(text []byte) []string {
+	if len(text) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+}
+
+// diffLines calculates the shortest edit script between original and candidate lines.
+// It returns false if texts have more than maxLines lines or more than maxDistance changed lines.
+func diffLines(original, candidate []string, maxLines, maxDistance int) ([]line, bool) {
+	if len(original)+len(candidate) > maxLines {
+		return nil, false
+	}
+
+	edits, ok := shortestEdit(len(original), len(candidate), func(x, y int) bool {
+		return original[x] == candidate[y]
+	}, maxDistance)
+
+	if !ok {
+		return nil, false
+	}
+
+	lines := make([]line, len(edits))
+	for i, e := range edits {
+		lines[i] = line{kind: e.kind, original: e.original, candidate: e.candidate}
+		if e.kind == insertedLine {
+			lines[i].text = candidate[e.candidate-1]
+		} else {
+			lines[i].text = original[e.original-1]
+		}
+	}
+
+	return lines, true
+}
+
+// hunks groups the changes with their context lines. Changes closer than two times the context lines are grouped in the same hunk.
+func hunks(lines []line, contextLines int) [][]line {
+	var groups [][]line
+	start, end := -1, -1
+
+	for i, l := range lines {
+		if l.kind == equalLine {
+			continue
+		}
+
+		if start >= 0 && i-contextLines > end {
+			groups = append(groups, lines[start:end+1])
+			start = -1
+		}
+
+		if start < 0 {
+			start = maximum(i-contextLines, 0)
+		}
+
+		end = i + contextLines
+		if end >= len(lines) {
+			end = len(lines) - 1
+		}
+	}
+
+	if start >= 0 {
+		groups = append(groups, lines[start:end+1])
+	}
+
+	return groups
+}
+
+func writeHunk(b *bytes.Buffer, hunk []line) {
+	originalStart, originalLength := 0, 0
+	candidateStart, candidateLength := 0, 0
+
+	for _, l := range hunk {
+		if l.kind != insertedLine {
+			if originalLength == 0 {
+				originalStart = l.original
+			}
+			originalLength++
+		}
+		if l.kind != deletedLine {
+			if candidateLength == 0 {
+				candidateStart = l.candidate
+			}
+			candidateLength++
+		}
+	}
+
+	// Empty ranges start at the line before the change
+	if originalLength == 0 {
+		originalStart = hunk[0].original
+	}
+	if candidateLength == 0 {
+		candidateStart = hunk[0].candidate
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(originalStart, originalLength), hunkRange(candidateStart, candidateLength))
+
+	for _, l := range hunk {
+		b.WriteByte(l.kind)
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+}
+
+func hunkRange(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
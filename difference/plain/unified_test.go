@@ -0,0 +1,200 @@
+package plain_test
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+
+	"github.com/lordofthejars/diferencia/difference/plain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unified Diff", func() {
+
+	Describe("Calculating unified diff", func() {
+		It("should return empty if texts are equal", func() {
+
+			// Given
+			text := []byte("a\nb\nc\n")
+
+			// When
+			result := plain.UnifiedDiff(text, text, plain.DefaultContextLines)
+
+			// Then
+			Expect(result).Should(BeEmpty())
+		})
+
+		It("should return changed lines with context", func() {
+
+			// Given
+			primary := []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\n")
+			candidate := []byte("one\ntwo\nthree\nfour\n5\nsix\nseven\neight\nnine\n")
+
+			// When
+			result := plain.UnifiedDiff(candidate, primary, 2)
+
+			// Then
+			Expect(result).Should(Equal(`--- primary
++++ candidate
+@@ -3,5 +3,5 @@
+ three
+ four
+-five
++5
+ six
+ seven`))
+		})
+
+		It("should split distant changes in different hunks", func() {
+
+			// Given
+			primary := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\n")
+			candidate := []byte("A\nb\nc\nd\ne\nf\ng\nh\ni\nj\n")
+
+			// When
+			result := plain.UnifiedDiff(candidate, primary, 1)
+
+			// Then
+			Expect(result).Should(Equal(`--- primary
++++ candidate
+@@ -1,2 +1,2 @@
+-a
++A
+ b
+@@ -9 +9,2 @@
+ i
++j`))
+		})
+
+		It("should return added lines in empty primary", func() {
+
+			// Given
+			primary := []byte("")
+			candidate := []byte("a\nb")
+
+			// When
+			result := plain.UnifiedDiff(candidate, primary, plain.DefaultContextLines)
+
+			// Then
+			Expect(result).Should(Equal(`--- primary
++++ candidate
+@@ -0,0 +1,2 @@
++a
++b`))
+		})
+
+		It("should return the minimum number of changed lines", func() {
+
+			random := rand.New(rand.NewSource(7))
+
+			for i := 0; i < 300; i++ {
+				// Given
+				primary := randomLines(random, random.Intn(30), "abc")
+				candidate := randomLines(random, random.Intn(30), "abc")
+
+				// When
+				result := plain.UnifiedDiff([]byte(strings.Join(candidate, "\n")), []byte(strings.Join(primary, "\n")), plain.DefaultContextLines)
+
+				// Then
+				Expect(changedLines(result)).Should(Equal(len(primary)+len(candidate)-2*longestCommonSubsequence(primary, candidate)), "primary %q candidate %q", primary, candidate)
+			}
+		})
+
+		It("should calculate differences of large texts in linear memory", func() {
+
+			// Given
+			var primary, candidate strings.Builder
+			for i := 0; i < 4000; i++ {
+				fmt.Fprintf(&primary, "line %d with some content\n", i)
+				if i%10 == 0 {
+					fmt.Fprintf(&candidate, "changed line %d\n", i)
+				} else {
+					fmt.Fprintf(&candidate, "line %d with some content\n", i)
+				}
+			}
+
+			// When
+			var result string
+			allocated := allocatedBytes(func() {
+				result = plain.UnifiedDiff([]byte(candidate.String()), []byte(primary.String()), plain.DefaultContextLines)
+			})
+
+			// Then
+			Expect(changedLines(result)).Should(Equal(800))
+			Expect(allocated).Should(BeNumerically("<", 10*1024*1024))
+		})
+
+		It("should only report that texts are different if they are too different", func() {
+
+			// Given
+			var primary, candidate strings.Builder
+			for i := 0; i < 4000; i++ {
+				fmt.Fprintf(&primary, "line %d\n", i)
+				fmt.Fprintf(&candidate, "other %d\n", i)
+			}
+
+			// When
+			var result string
+			allocated := allocatedBytes(func() {
+				result = plain.UnifiedDiff([]byte(candidate.String()), []byte(primary.String()), plain.DefaultContextLines)
+			})
+
+			// Then
+			Expect(result).Should(Equal("Texts are different (4000 lines in primary, 4000 lines in candidate) but they are too large or too different to show their differences"))
+			Expect(allocated).Should(BeNumerically("<", 10*1024*1024))
+		})
+	})
+})
+
+func randomLines(random *rand.Rand, count int, alphabet string) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = string(alphabet[random.Intn(len(alphabet))])
+	}
+	return lines
+}
+
+// changedLines counts deleted and inserted lines of a unified diff
+func changedLines(diff string) int {
+	changed := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if line == "--- primary" || line == "+++ candidate" {
+			continue
+		}
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+			changed++
+		}
+	}
+	return changed
+}
+
+func longestCommonSubsequence(a, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				lengths[i][j] = lengths[i-1][j-1] + 1
+			} else if lengths[i-1][j] > lengths[i][j-1] {
+				lengths[i][j] = lengths[i-1][j]
+			} else {
+				lengths[i][j] = lengths[i][j-1]
+			}
+		}
+	}
+	return lengths[len(a)][len(b)]
+}
+
+// allocatedBytes returns the bytes allocated while running f
+func allocatedBytes(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
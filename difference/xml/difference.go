@@ -0,0 +1,174 @@
+package xml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const undefinedValue = "undefined"
+
+// difference between primary and candidate document located by an XPath expression
+type difference struct {
+	path      string
+	primary   string
+	candidate string
+	// structural differences are elements or attributes present only in one of the documents
+	structural bool
+}
+
+func (d difference) String() string {
+	return fmt.Sprintf(`"%s": %s => %s`, d.path, d.primary, d.candidate)
+}
+
+// CompareDocuments comparing two XML documents and returns true or false according to configured difference
+func CompareDocuments(candidate, original []byte, difference string) (bool, string) {
+	return CompareDocumentsIgnoring(candidate, original, difference, nil)
+}
+
+// CompareDocumentsIgnoring comparing two XML documents without taking into consideration the nodes selected by the XPath expressions.
+// In Schema mode, only elements and attributes present in one of the documents are reported.
+// It returns the list of differences in the form of `"xpath": primary => candidate`
+func CompareDocumentsIgnoring(candidate, original []byte, difference string, ignoredNodes []string) (bool, string) {
+
+	candidateDocument, err := prepare(candidate, ignoredNodes)
+	if err != nil {
+		return false, fmt.Sprintf("first argument is invalid xml. %s", err.Error())
+	}
+
+	originalDocument, err := prepare(original, ignoredNodes)
+	if err != nil {
+		return false, fmt.Sprintf("second argument is invalid xml. %s", err.Error())
+	}
+
+	differences := compareDocuments(originalDocument, candidateDocument, difference == "Subset")
+
+	var lines []string
+	for _, d := range differences {
+		if difference == "Schema" && !d.structural {
+			continue
+		}
+		lines = append(lines, d.String())
+	}
+
+	return len(lines) == 0, strings.Join(lines, "\n")
+}
+
+func compareDocuments(original, candidate *element, subset bool) []difference {
+
+	var differences []difference
+
+	if original.name != candidate.name {
+		return append(differences, difference{path: "/", primary: describeElement(original), candidate: describeElement(candidate), structural: true})
+	}
+
+	compareElements(original, candidate, "/"+elementStep(original.name, 1, 1), subset, &differences)
+	return differences
+}
+
+// compareElements compares attributes, text and children. Children are matched by name and position between siblings with same name.
+// In subset mode, attributes and children only present in candidate are not reported.
+func compareElements(original, candidate *element, path string, subset bool, differences *[]difference) {
+
+	for _, name := range sortedNames(original.attributes) {
+		attributePath := path + "/" + attributeStep(name)
+		candidateValue, ok := candidate.attributes[name]
+		switch {
+		case !ok:
+			*differences = append(*differences, difference{path: attributePath, primary: fmt.Sprintf("%q", original.attributes[name]), candidate: undefinedValue, structural: true})
+		case candidateValue != original.attributes[name]:
+			*differences = append(*differences, difference{path: attributePath, primary: fmt.Sprintf("%q", original.attributes[name]), candidate: fmt.Sprintf("%q", candidateValue)})
+		}
+	}
+
+	if !subset {
+		for _, name := range sortedNames(candidate.attributes) {
+			if _, ok := original.attributes[name]; !ok {
+				*differences = append(*differences, difference{path: path + "/" + attributeStep(name), primary: undefinedValue, candidate: fmt.Sprintf("%q", candidate.attributes[name]), structural: true})
+			}
+		}
+	}
+
+	if original.text != candidate.text {
+		*differences = append(*differences, difference{path: path + "/text()", primary: fmt.Sprintf("%q", original.text), candidate: fmt.Sprintf("%q", candidate.text)})
+	}
+
+	originalChildren := groupByName(original.children)
+	candidateChildren := groupByName(candidate.children)
+
+	for _, name := range unionOfNames(original.children, candidate.children) {
+		originalGroup := originalChildren[name]
+		candidateGroup := candidateChildren[name]
+
+		for i := 0; i < maximum(len(originalGroup), len(candidateGroup)); i++ {
+			childPath := path + "/" + elementStep(name, i+1, maximum(len(originalGroup), len(candidateGroup)))
+			switch {
+			case i < len(originalGroup) && i < len(candidateGroup):
+				compareElements(originalGroup[i], candidateGroup[i], childPath, subset, differences)
+			case i < len(originalGroup):
+				*differences = append(*differences, difference{path: childPath, primary: describeElement(originalGroup[i]), candidate: undefinedValue, structural: true})
+			case !subset:
+				*differences = append(*differences, difference{path: childPath, primary: undefinedValue, candidate: describeElement(candidateGroup[i]), structural: true})
+			}
+		}
+	}
+}
+
+func groupByName(elements []*element) map[xml.Name][]*element {
+	groups := make(map[xml.Name][]*element)
+	for _, e := range elements {
+		groups[e.name] = append(groups[e.name], e)
+	}
+	return groups
+}
+
+// unionOfNames returns the names of the elements in order of appearance, first the ones of original and then the ones only present in candidate
+func unionOfNames(original, candidate []*element) []xml.Name {
+	seen := make(map[xml.Name]bool)
+	var names []xml.Name
+	for _, elements := range [][]*element{original, candidate} {
+		for _, e := range elements {
+			if !seen[e.name] {
+				seen[e.name] = true
+				names = append(names, e.name)
+			}
+		}
+	}
+	return names
+}
+
+// elementStep returns the XPath step selecting the element. Elements with namespace are selected using local-name and namespace-uri functions
+// so the expression does not depend on the prefixes used by each document. Position is only added when there is more than one sibling with same name.
+func elementStep(name xml.Name, position, siblings int) string {
+	step := name.Local
+	if len(name.Space) > 0 {
+		step = fmt.Sprintf("*[local-name()='%s' and namespace-uri()='%s']", name.Local, name.Space)
+	}
+
+	if siblings > 1 {
+		step = fmt.Sprintf("%s[%d]", step, position)
+	}
+
+	return step
+}
+
+func attributeStep(name xml.Name) string {
+	if len(name.Space) > 0 {
+		return fmt.Sprintf("@*[local-name()='%s' and namespace-uri()='%s']", name.Local, name.Space)
+	}
+	return "@" + name.Local
+}
+
+func describeElement(e *element) string {
+	if len(e.name.Space) > 0 {
+		return fmt.Sprintf("<{%s}%s>", e.name.Space, e.name.Local)
+	}
+	return fmt.Sprintf("<%s>", e.name.Local)
+}
+
+func maximum(x, y int) int {
+	if x < y {
+		return y
+	}
+	return x
+}
@@ -0,0 +1,149 @@
+package xml_test
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/xml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Xml Difference", func() {
+
+	Describe("Compare Two Equal Xml documents", func() {
+		Context("With strict mode", func() {
+			It("should return that are equal", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+
+				result, output := xml.CompareDocuments(documentA, documentA, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are equal when only prefixes, attribute order, comments or whitespaces change", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-other-prefix.xml")
+
+				result, output := xml.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Compare Two Different Xml documents", func() {
+		Context("With strict mode", func() {
+			It("should return that are different by value", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-changed.xml")
+
+				result, output := xml.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring(`"/*[local-name()='catalog' and namespace-uri()='http://diferencia.io/catalog']/*[local-name()='book' and namespace-uri()='http://diferencia.io/catalog'][2]/@lang": "en" => "es"`))
+				Expect(output).Should(ContainSubstring(`/*[local-name()='price' and namespace-uri()='http://diferencia.io/catalog']/text()": "5.95" => "6.95"`))
+			})
+
+			It("should return that are different when namespace changes", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-other-namespace.xml")
+
+				result, output := xml.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(Equal(`"/": <{http://diferencia.io/catalog}catalog> => <{http://diferencia.io/catalog/v2}catalog>`))
+			})
+
+			It("should return that are different when candidate contains more elements", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-superset.xml")
+
+				result, output := xml.CompareDocuments(documentB, documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring(`/@updated": undefined => "2018-06-18"`))
+				Expect(output).Should(ContainSubstring(`[3]": undefined => <{http://diferencia.io/catalog}book>`))
+			})
+
+			It("should return that are different when document is not valid", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+
+				result, output := xml.CompareDocuments([]byte("<catalog>"), documentA, core.Strict.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring("first argument is invalid xml"))
+			})
+		})
+
+		Context("With subset mode", func() {
+			It("should return that are equal when candidate contains more elements and attributes", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-superset.xml")
+
+				result, output := xml.CompareDocuments(documentB, documentA, core.Subset.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when candidate contains less elements", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-superset.xml")
+
+				result, output := xml.CompareDocuments(documentA, documentB, core.Subset.String())
+				Expect(result).To(Equal(false))
+				Expect(output).Should(ContainSubstring(`": <{http://diferencia.io/catalog}author> => undefined`))
+			})
+		})
+
+		Context("With schema mode", func() {
+			It("should return that are equal when only values change", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-changed.xml")
+
+				result, output := xml.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return that are different when an element is added", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-superset.xml")
+
+				result, _ := xml.CompareDocuments(documentB, documentA, core.Schema.String())
+				Expect(result).To(Equal(false))
+			})
+		})
+
+		Context("With ignored nodes", func() {
+			It("should return that are equal when different nodes are ignored", func() {
+				documentA := loadFromFile("test_fixtures/catalog.xml")
+				documentB := loadFromFile("test_fixtures/catalog-changed.xml")
+
+				result, output := xml.CompareDocumentsIgnoring(documentB, documentA, core.Strict.String(), []string{"//*[local-name()='price']", "//@lang"})
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Validating XPath expressions", func() {
+		It("should accept valid expressions", func() {
+			for _, expression := range []string{"//timestamp", "//order/@id", "//item[contains(@name, 'x')]/text()"} {
+				Expect(xml.ValidateXPath(expression)).Should(Succeed())
+			}
+		})
+
+		It("should fail with invalid expressions", func() {
+			Expect(xml.ValidateXPath("//order[")).Should(HaveOccurred())
+		})
+	})
+
+})
+
+func loadFromFile(filePath string) []byte {
+	payload, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		Fail(fmt.Sprintf("Unable to load test fixture. Reason: %q", err))
+	}
+	return payload
+}
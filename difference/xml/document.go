@@ -0,0 +1,217 @@
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// element is the canonical representation of an XML element where prefixes, namespace declarations, comments,
+// processing instructions and whitespace between elements are not taken into consideration.
+type element struct {
+	name       xml.Name
+	attributes map[xml.Name]string
+	text       string
+	children   []*element
+}
+
+func parse(document []byte) (*xmlquery.Node, error) {
+	return xmlquery.Parse(bytes.NewReader(document))
+}
+
+// canonicalize transforms the parsed document to its canonical representation
+func canonicalize(document *xmlquery.Node) (*element, error) {
+
+	var root *element
+
+	for node := document.FirstChild; node != nil; node = node.NextSibling {
+		if node.Type == xmlquery.ElementNode {
+			if root != nil {
+				return nil, errors.New("XML document contains more than one root element")
+			}
+			root = toElement(node)
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("XML document does not contain any root element")
+	}
+
+	return root, nil
+}
+
+func toElement(node *xmlquery.Node) *element {
+
+	current := &element{
+		name:       xml.Name{Space: node.NamespaceURI, Local: node.Data},
+		attributes: make(map[xml.Name]string),
+	}
+
+	for _, attribute := range node.Attr {
+		if isNamespaceDeclaration(attribute) {
+			continue
+		}
+		current.attributes[xml.Name{Space: attribute.NamespaceURI, Local: attribute.Name.Local}] = attribute.Value
+	}
+
+	var text []string
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case xmlquery.ElementNode:
+			current.children = append(current.children, toElement(child))
+		case xmlquery.TextNode, xmlquery.CharDataNode:
+			if trimmed := strings.TrimSpace(child.Data); len(trimmed) > 0 {
+				text = append(text, trimmed)
+			}
+		}
+	}
+	current.text = strings.Join(text, "")
+
+	return current
+}
+
+func isNamespaceDeclaration(attribute xmlquery.Attr) bool {
+	return (attribute.Name.Space == "" && attribute.Name.Local == "xmlns") || attribute.NamespaceURI == "xmlns"
+}
+
+// marshal serializes the canonical representation. Namespaces are declared by the encoder, so prefixes used in original document are not kept.
+func (e *element) marshal() ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := xml.NewEncoder(&buffer)
+
+	if err := e.encode(encoder); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (e *element) encode(encoder *xml.Encoder) error {
+
+	start := xml.StartElement{Name: e.name}
+	for _, name := range sortedNames(e.attributes) {
+		start.Attr = append(start.Attr, xml.Attr{Name: name, Value: e.attributes[name]})
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if len(e.text) > 0 {
+		if err := encoder.EncodeToken(xml.CharData(e.text)); err != nil {
+			return err
+		}
+	}
+
+	for _, child := range e.children {
+		if err := child.encode(encoder); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+func sortedNames(attributes map[xml.Name]string) []xml.Name {
+	var names []xml.Name
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+		return names[i].Local < names[j].Local
+	})
+	return names
+}
+
+// ValidateXPath checks that the XPath expression is valid
+func ValidateXPath(expression string) error {
+	_, err := compileXPath(expression)
+	return err
+}
+
+func compileXPath(expression string) (*xpath.Expr, error) {
+	compiled, err := xpath.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("XPath expression %s is not valid. %s", expression, err.Error())
+	}
+	return compiled, nil
+}
+
+// removeNodes removes from document all nodes (elements, attributes or texts) selected by any of the XPath expressions
+func removeNodes(document *xmlquery.Node, expressions []string) error {
+
+	type attributeNode struct {
+		element *xmlquery.Node
+		name    xml.Name
+	}
+
+	var nodes []*xmlquery.Node
+	var attributes []attributeNode
+
+	for _, expression := range expressions {
+		compiled, err := compileXPath(expression)
+		if err != nil {
+			return err
+		}
+
+		// Selected nodes are removed after iterating so the iterator is not affected by the removal
+		iterator := compiled.Select(xmlquery.CreateXPathNavigator(document))
+		for iterator.MoveNext() {
+			navigator := iterator.Current().(*xmlquery.NodeNavigator)
+			switch navigator.NodeType() {
+			case xpath.AttributeNode:
+				attributes = append(attributes, attributeNode{element: navigator.Current(), name: xml.Name{Space: navigator.NamespaceURL(), Local: navigator.LocalName()}})
+			case xpath.ElementNode, xpath.TextNode:
+				nodes = append(nodes, navigator.Current())
+			}
+		}
+	}
+
+	for _, attribute := range attributes {
+		removeAttribute(attribute.element, attribute.name)
+	}
+
+	for _, node := range nodes {
+		xmlquery.RemoveFromTree(node)
+	}
+
+	return nil
+}
+
+func removeAttribute(node *xmlquery.Node, name xml.Name) {
+	var attributes []xmlquery.Attr
+	for _, attribute := range node.Attr {
+		if attribute.NamespaceURI != name.Space || attribute.Name.Local != name.Local {
+			attributes = append(attributes, attribute)
+		}
+	}
+	node.Attr = attributes
+}
+
+// prepare parses the document, removes the nodes selected by the XPath expressions and returns its canonical representation
+func prepare(document []byte, ignoredNodes []string) (*element, error) {
+
+	parsed, err := parse(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := removeNodes(parsed, ignoredNodes); err != nil {
+		return nil, err
+	}
+
+	return canonicalize(parsed)
+}
@@ -0,0 +1,70 @@
+package xml
+
+import (
+	"fmt"
+)
+
+// NoiseOperation struct
+type NoiseOperation struct {
+	// XPaths of the nodes considered noise
+	XPaths []string
+}
+
+// Initialize with some XPath expressions
+func (nd *NoiseOperation) Initialize(xpaths []string) {
+	nd.XPaths = append(nd.XPaths, xpaths...)
+}
+
+// ContainsNoise method
+func (nd NoiseOperation) ContainsNoise() bool {
+	return len(nd.XPaths) > 0
+}
+
+// Detect Noise between documents. Only texts and attributes with different values are considered noise.
+func (nd *NoiseOperation) Detect(primary, secondary []byte) error {
+
+	primaryDocument, err := prepare(primary, nd.XPaths)
+	if err != nil {
+		return err
+	}
+
+	secondaryDocument, err := prepare(secondary, nd.XPaths)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range compareDocuments(primaryDocument, secondaryDocument, false) {
+		if d.structural {
+			return fmt.Errorf("Primary and Secondary payload contains other changes apart from replacing values %s", d.String())
+		}
+		nd.XPaths = append(nd.XPaths, d.path)
+	}
+
+	return nil
+}
+
+// Remove noise from primary and candidate documents
+func (nd *NoiseOperation) Remove(primary, candidate []byte) ([]byte, []byte, error) {
+
+	primaryDocument, err := prepare(primary, nd.XPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateDocument, err := prepare(candidate, nd.XPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	primaryWithoutNoise, err := primaryDocument.marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidateWithoutNoise, err := candidateDocument.marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return primaryWithoutNoise, candidateWithoutNoise, nil
+}
@@ -0,0 +1,85 @@
+package xml_test
+
+import (
+	"github.com/lordofthejars/diferencia/core"
+
+	"github.com/lordofthejars/diferencia/difference/xml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Xml Noise Operation", func() {
+
+	Describe("Detect noise", func() {
+		Context("With documents with different values", func() {
+			It("should detect texts and attributes as noise", func() {
+				primary := loadFromFile("test_fixtures/envelope.xml")
+				secondary := loadFromFile("test_fixtures/envelope-secondary.xml")
+
+				noiseOperation := xml.NoiseOperation{}
+				err := noiseOperation.Detect(primary, secondary)
+
+				Expect(err).Should(Succeed())
+				Expect(noiseOperation.XPaths).Should(HaveLen(2))
+				Expect(noiseOperation.XPaths).Should(ContainElement(ContainSubstring("/@*[local-name()='requestId' and namespace-uri()='http://diferencia.io/trace']")))
+				Expect(noiseOperation.XPaths).Should(ContainElement(ContainSubstring("/*[local-name()='Timestamp' and namespace-uri()='http://diferencia.io/prices']/text()")))
+			})
+
+			It("should fail when documents have different structure", func() {
+				primary := loadFromFile("test_fixtures/envelope.xml")
+				secondary := loadFromFile("test_fixtures/envelope-structural.xml")
+
+				noiseOperation := xml.NoiseOperation{}
+				err := noiseOperation.Detect(primary, secondary)
+
+				Expect(err).ShouldNot(Succeed())
+			})
+		})
+	})
+
+	Describe("Remove noise", func() {
+		Context("With detected noise", func() {
+			It("should return equal documents even if prefixes are different", func() {
+				primary := loadFromFile("test_fixtures/envelope.xml")
+				secondary := loadFromFile("test_fixtures/envelope-secondary.xml")
+				candidate := loadFromFile("test_fixtures/envelope-candidate.xml")
+
+				noiseOperation := xml.NoiseOperation{}
+				noiseOperation.Detect(primary, secondary)
+				primaryWithoutNoise, candidateWithoutNoise, err := noiseOperation.Remove(primary, candidate)
+
+				Expect(err).Should(Succeed())
+				result, output := xml.CompareDocuments(candidateWithoutNoise, primaryWithoutNoise, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+		})
+
+		Context("With manual noise", func() {
+			It("should remove nodes selected by XPath expressions", func() {
+				primary := loadFromFile("test_fixtures/catalog.xml")
+				candidate := loadFromFile("test_fixtures/catalog-changed.xml")
+
+				noiseOperation := xml.NoiseOperation{}
+				noiseOperation.Initialize([]string{"//*[local-name()='book'][2]"})
+				primaryWithoutNoise, candidateWithoutNoise, err := noiseOperation.Remove(primary, candidate)
+
+				Expect(err).Should(Succeed())
+				result, _ := xml.CompareDocuments(candidateWithoutNoise, primaryWithoutNoise, core.Strict.String())
+				Expect(result).To(Equal(true))
+			})
+
+			It("should fail when XPath expression is not valid", func() {
+				primary := loadFromFile("test_fixtures/catalog.xml")
+
+				noiseOperation := xml.NoiseOperation{}
+				noiseOperation.Initialize([]string{"//*["})
+				_, _, err := noiseOperation.Remove(primary, primary)
+
+				Expect(err).ShouldNot(Succeed())
+			})
+		})
+	})
+
+})
@@ -0,0 +1,13 @@
+package xml_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiferenciaXml(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diferencia XML Suite")
+}
@@ -0,0 +1,71 @@
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// IsYaml checks if content type is application/yaml, application/x-yaml, text/yaml, text/x-yaml or any type with +yaml suffix
+func IsYaml(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+
+	return strings.HasSuffix(mediaType, "+yaml")
+}
+
+// ToJson transforms a YAML document to a JSON document so it can be compared using JSON difference and noise detection.
+// Since JSON is a subset of YAML, transforming a JSON document returns an equivalent JSON document.
+func ToJson(document []byte) ([]byte, error) {
+
+	var value interface{}
+	if err := yaml.Unmarshal(document, &value); err != nil {
+		return nil, err
+	}
+
+	converted, err := toJsonValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(converted)
+}
+
+// toJsonValue converts YAML mappings, which might have keys of any type, to JSON objects
+func toJsonValue(value interface{}) (interface{}, error) {
+
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(typedValue))
+		for key, child := range typedValue {
+			converted, err := toJsonValue(child)
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(key)] = converted
+		}
+		return object, nil
+	case []interface{}:
+		array := make([]interface{}, len(typedValue))
+		for i, child := range typedValue {
+			converted, err := toJsonValue(child)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = converted
+		}
+		return array, nil
+	}
+
+	return value, nil
+}
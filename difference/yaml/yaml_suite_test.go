@@ -0,0 +1,13 @@
+package yaml_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiferenciaYaml(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diferencia YAML Suite")
+}
@@ -0,0 +1,66 @@
+package yaml_test
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/lordofthejars/diferencia/core"
+	"github.com/lordofthejars/diferencia/difference/json"
+
+	"github.com/lordofthejars/diferencia/difference/yaml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Yaml Conversion", func() {
+
+	Describe("Convert Yaml documents to Json", func() {
+		Context("With a valid document", func() {
+			It("should return equivalent Json document", func() {
+				document := loadFromFile("test_fixtures/config-a.yaml")
+				expected := loadFromFile("test_fixtures/config-a.json")
+
+				converted, err := yaml.ToJson(document)
+				Expect(err).Should(Succeed())
+
+				result, output := json.CompareDocuments(converted, expected, core.Strict.String())
+				Expect(result).To(Equal(true))
+				Expect(len(output)).To(Equal(0))
+			})
+
+			It("should return same document when it is already a Json document", func() {
+				expected := loadFromFile("test_fixtures/config-a.json")
+
+				converted, err := yaml.ToJson(expected)
+				Expect(err).Should(Succeed())
+				Expect(converted).Should(MatchJSON(expected))
+			})
+		})
+
+		Context("With an invalid document", func() {
+			It("should return an error", func() {
+				_, err := yaml.ToJson([]byte("service: [payments"))
+				Expect(err).ShouldNot(Succeed())
+			})
+		})
+	})
+
+	Describe("Detect Yaml content type", func() {
+		It("should accept Yaml media types", func() {
+			Expect(yaml.IsYaml("application/yaml")).To(Equal(true))
+			Expect(yaml.IsYaml("application/x-yaml; charset=utf-8")).To(Equal(true))
+			Expect(yaml.IsYaml("application/openapi+yaml")).To(Equal(true))
+			Expect(yaml.IsYaml("application/json")).To(Equal(false))
+		})
+	})
+
+})
+
+func loadFromFile(filePath string) []byte {
+	payload, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		Fail(fmt.Sprintf("Unable to load test fixture. Reason: %q", err))
+	}
+	return payload
+}
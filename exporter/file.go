@@ -2,24 +2,32 @@ package exporter
 
 import (
 	"bufio"
+	"encoding/base64"
 	"encoding/json"
 	"os"
 	"time"
 )
 
+// Base64 encoding of binary contents
+const Base64 = "base64"
+
+// Interaction with a service. Content of binary bodies is stored base64-encoded and Encoding is set
 type Interaction struct {
 	URL        string `json:"url"`
 	Content    string `json:"content"`
+	Encoding   string `json:"encoding,omitempty"`
 	StatusCode int    `json:"status"`
 }
 
 type Interactions struct {
-	Primary        Interaction  `json:"primary"`
-	Secondary      *Interaction `json:"secondary,omitempty"`
-	Candidate      Interaction  `json:"candidate"`
-	DifferenceMode string       `json:"differenceMode"`
-	Result         bool         `json:"result"`
-	Processed      time.Time    `json:"processedDate"`
+	Primary        Interaction     `json:"primary"`
+	Secondary      *Interaction    `json:"secondary,omitempty"`
+	Candidate      Interaction     `json:"candidate"`
+	DifferenceMode string          `json:"differenceMode"`
+	Result         bool            `json:"result"`
+	Processed      time.Time       `json:"processedDate"`
+	BodyDiff       string          `json:"bodyDiff,omitempty"`
+	BodyPatch      json.RawMessage `json:"bodyPatch,omitempty"`
 }
 
 func CreateInteraction(url string, content []byte, statusCode int) Interaction {
@@ -30,6 +38,24 @@ func CreateInteraction(url string, content []byte, statusCode int) Interaction {
 	}
 }
 
+// CreateBinaryInteraction creates an interaction with its content base64-encoded
+func CreateBinaryInteraction(url string, content []byte, statusCode int) Interaction {
+	return Interaction{
+		URL:        url,
+		Content:    base64.StdEncoding.EncodeToString(content),
+		Encoding:   Base64,
+		StatusCode: statusCode,
+	}
+}
+
+// Body returns the content of the interaction decoded with its encoding
+func (interaction Interaction) Body() ([]byte, error) {
+	if interaction.Encoding == Base64 {
+		return base64.StdEncoding.DecodeString(interaction.Content)
+	}
+	return []byte(interaction.Content), nil
+}
+
 func CreateInteractions(primary Interaction, secondary *Interaction, candidate Interaction, differenceMode string, result bool) Interactions {
 	interactions := Interactions{
 		Primary:        primary,
@@ -107,5 +107,53 @@ var _ = Describe("File Exporter", func() {
 				Expect(expectedInteractions.Result).Should(Equal(interactions.Result))
 			})
 		})
+
+		Context("With text interactions", func() {
+			It("should store content as string", func() {
+				tmpfile, err := ioutil.TempFile("", "log.json")
+
+				if err != nil {
+					Fail(fmt.Sprintf("Unable to create temporal file. Reason: %q", err))
+				}
+				defer os.Remove(tmpfile.Name())
+
+				textContent := []byte(`{"page": 1}`)
+				interactions := exporter.CreateInteractions(exporter.CreateInteraction("http://localhost:8080", textContent, 200), nil, exporter.CreateInteraction("http://localhost:8082", textContent, 200), core.Strict.String(), true)
+				err = exporter.ExportToFile(tmpfile.Name(), interactions)
+				if err != nil {
+					Fail(fmt.Sprintf("Unable to export results. Reason: %q", err))
+				}
+
+				byt, err := ioutil.ReadFile(tmpfile.Name())
+
+				Expect(string(byt)).Should(ContainSubstring(`"content":"{\"page\": 1}"`))
+				Expect(string(byt)).ShouldNot(ContainSubstring(`"encoding"`))
+			})
+		})
+
+		Context("With binary interactions", func() {
+			It("should store content base64-encoded", func() {
+				tmpfile, err := ioutil.TempFile("", "log.json")
+
+				if err != nil {
+					Fail(fmt.Sprintf("Unable to create temporal file. Reason: %q", err))
+				}
+				defer os.Remove(tmpfile.Name())
+
+				binaryContent := []byte{0x25, 0x50, 0x44, 0x46, 0x00, 0xff}
+				interactions := exporter.CreateInteractions(exporter.CreateBinaryInteraction("http://localhost:8080", binaryContent, 200), nil, exporter.CreateBinaryInteraction("http://localhost:8082", binaryContent, 200), core.Strict.String(), true)
+				err = exporter.ExportToFile(tmpfile.Name(), interactions)
+				if err != nil {
+					Fail(fmt.Sprintf("Unable to export results. Reason: %q", err))
+				}
+
+				byt, err := ioutil.ReadFile(tmpfile.Name())
+				expectedInteractions := &exporter.Interactions{}
+				json.Unmarshal(byt, expectedInteractions)
+
+				Expect(string(byt)).Should(ContainSubstring(`"content":"JVBERgD/","encoding":"base64"`))
+				Expect(expectedInteractions.Primary.Body()).Should(Equal(binaryContent))
+			})
+		})
 	})
 })
@@ -25,12 +25,16 @@ type CallData struct {
 
 // ErrorData to hold all info when an error occurs
 type ErrorData struct {
-	FullURI         string      `json:"fullURI"`
-	OriginalBody    string      `json:"originalBody,omitempty"`
-	OriginalHeaders http.Header `json:"originalHeaders,omitempty"`
-	HeaderDiff      string      `json:"headerDiff,omitempty"`
-	BodyDiff        string      `json:"bodyDiff,omitempty"`
-	StatusDiff      string      `json:"statusDiff,omitempty"`
+	FullURI         string          `json:"fullURI"`
+	OriginalBody    string          `json:"originalBody,omitempty"`
+	OriginalHeaders http.Header     `json:"originalHeaders,omitempty"`
+	HeaderDiff      string          `json:"headerDiff,omitempty"`
+	BodyDiff        string          `json:"bodyDiff,omitempty"`
+	BodyPatch       json.RawMessage `json:"bodyPatch,omitempty"`
+	StatusDiff      string          `json:"statusDiff,omitempty"`
+	SchemaDiff      string          `json:"schemaDiff,omitempty"`
+	CharsetDiff     string          `json:"charsetDiff,omitempty"`
+	MatcherDiff     string          `json:"matcherDiff,omitempty"`
 }
 
 // IncError increments the error counter
@@ -238,6 +242,11 @@ func IncrementSuccess(method, path string, primaryAverage, candidateAverage time
 func IncrementError(method, path, body, uri, headersDiff, bodyDiff, stautsDiff string, headers http.Header) int {
 	errorData := ErrorData{FullURI: uri, OriginalBody: body, OriginalHeaders: headers, HeaderDiff: headersDiff, BodyDiff: bodyDiff, StatusDiff: stautsDiff}
 
+	return IncrementErrorData(method, path, errorData)
+}
+
+// IncrementErrorData stats with a new error using already filled error data
+func IncrementErrorData(method, path string, errorData ErrorData) int {
 	return stats.IncErr(method, path, errorData)
 }
 
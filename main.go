@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"strings"
 
 	"github.com/lordofthejars/diferencia/core"
 	"github.com/lordofthejars/diferencia/log"
@@ -18,7 +19,21 @@ func areHttpsClientAttributesCorrect(caCert, clientCert, clientKey string) bool
 	return (len(caCert) == 0 && len(clientCert) == 0 && len(clientKey) == 0) || (len(caCert) > 0 && len(clientCert) > 0 && len(clientKey) > 0)
 }
 
-func main() {
+// splitIgnoreValues splits JSON Pointers separated by commas. JSONPath expressions, which start with $, are kept as they are since they can contain commas.
+func splitIgnoreValues(values []string) []string {
+	var ignoreValues []string
+	for _, value := range values {
+		if strings.HasPrefix(value, "$") {
+			ignoreValues = append(ignoreValues, value)
+		} else {
+			ignoreValues = append(ignoreValues, strings.Split(value, ",")...)
+		}
+	}
+	return ignoreValues
+}
+
+// newStartCommand creates the command that starts Diferencia with its flags
+func newStartCommand() *cobra.Command {
 
 	var port int
 	var serviceName, primaryURL, secondaryURL, candidateURL, difference string
@@ -36,6 +51,27 @@ func main() {
 	var levenshteinPercentage int
 	var forcePlainText, mirroring bool
 	var returnResult bool
+	var jsonSchema string
+	var jsonSchemas []string
+	var unorderedArrays []string
+	var arrayKeys []string
+	var numericTolerances []string
+	var defaultTolerance string
+	var valueMatchers []string
+	var bodyDiffFormat string
+	var ignoreXPaths []string
+	var ignoreSelectors []string
+	var comparators []string
+	var compareContentEncoding bool
+	var maxDecodedBodySize int
+	var textSimilarity string
+	var ignoreTextPatterns []string
+	var ignoreTextPatternsFile string
+
+	var secondaryQuorum int
+	var noiseMode string
+	var learnNoise bool
+	var noiseStableDetections, noiseRecheckInterval int
 
 	var adminPort int
 
@@ -49,15 +85,20 @@ func main() {
 			config.Port = port
 			config.Primary = primaryURL
 			config.Secondary = secondaryURL
+			config.SecondaryQuorum = secondaryQuorum
 			config.Candidate = candidateURL
 			config.StoreResults = storeResults
 			config.NoiseDetection = noiseDetection
+			config.NoiseMode = noiseMode
+			config.LearnNoise = learnNoise
+			config.NoiseStableDetections = noiseStableDetections
+			config.NoiseRecheckInterval = noiseRecheckInterval
 			config.AllowUnsafeOperations = allowUnsafeOperations
 			config.Headers = headers
 			config.IgnoreHeadersValues = ignoreHeadersValues
 			config.Prometheus = prometheus
 			config.PrometheusPort = prometheusPort
-			config.IgnoreValues = ignoreValuesOf
+			config.IgnoreValues = splitIgnoreValues(ignoreValuesOf)
 			config.IgnoreValuesFile = ignoreValuesFile
 			config.InsecureSkipVerify = insecureSkipVerify
 			config.CaCert = caCert
@@ -68,6 +109,22 @@ func main() {
 			config.LevenshteinPercentage = levenshteinPercentage
 			config.Mirroring = mirroring
 			config.ReturnResult = returnResult
+			config.JsonSchema = jsonSchema
+			config.JsonSchemas = jsonSchemas
+			config.UnorderedArrays = unorderedArrays
+			config.ArrayKeys = arrayKeys
+			config.NumericTolerances = numericTolerances
+			config.DefaultTolerance = defaultTolerance
+			config.ValueMatchers = valueMatchers
+			config.BodyDiffFormat = bodyDiffFormat
+			config.IgnoreXPaths = ignoreXPaths
+			config.IgnoreSelectors = ignoreSelectors
+			config.Comparators = comparators
+			config.CompareContentEncoding = compareContentEncoding
+			config.MaxDecodedBodySize = maxDecodedBodySize
+			config.TextSimilarity = textSimilarity
+			config.IgnoreTextPatterns = ignoreTextPatterns
+			config.IgnoreTextPatternsFile = ignoreTextPatternsFile
 
 			differenceMode, err := core.NewDifference(difference)
 
@@ -77,6 +134,61 @@ func main() {
 			}
 			config.DifferenceMode = differenceMode
 
+			if differenceMode == core.JsonSchema && !config.IsJsonSchemaSet() {
+				logrus.Errorf("If JsonSchema difference mode is enabled, you need to provide a JSON Schema either globally or per endpoint")
+				os.Exit(1)
+			}
+
+			if _, err := config.ArrayKeysByPointer(); err != nil {
+				logrus.Errorf("Error while setting array keys. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if _, err := config.TolerancesByPointer(); err != nil {
+				logrus.Errorf("Error while setting numeric tolerances. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if _, err := config.GetDefaultTolerance(); err != nil {
+				logrus.Errorf("Error while setting default tolerance. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if _, err := config.ValueMatchersByPointer(); err != nil {
+				logrus.Errorf("Error while setting value matchers. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if err := config.ValidateIgnoreValues(); err != nil {
+				logrus.Errorf("Error while setting ignore values. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if err := config.ValidateIgnoreXPaths(); err != nil {
+				logrus.Errorf("Error while setting ignore XPaths. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if err := config.ValidateIgnoreSelectors(); err != nil {
+				logrus.Errorf("Error while setting ignore selectors. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if _, err := config.ComparatorsByMediaType(); err != nil {
+				logrus.Errorf("Error while setting comparators. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if _, _, err := config.GetTextSimilarity(); err != nil {
+				logrus.Errorf("Error while setting text similarity. %s", err.Error())
+				os.Exit(1)
+			}
+
+			if err := config.ValidateBodyDiffFormat(); err != nil {
+				logrus.Errorf("Error while setting body diff format. %s", err.Error())
+				os.Exit(1)
+			}
+
 			if mirroring && returnResult {
 				logrus.Errorf("You cannot set Returning Result of comparision and mirroring at the same time.")
 				os.Exit(1)
@@ -92,6 +204,25 @@ func main() {
 				os.Exit(1)
 			}
 
+			if noiseDetection {
+				if err := config.ValidateSecondaryQuorum(); err != nil {
+					logrus.Errorf("Error while setting secondary quorum. %s", err.Error())
+					os.Exit(1)
+				}
+
+				if err := config.ValidateNoiseMode(); err != nil {
+					logrus.Errorf("Error while setting noise mode. %s", err.Error())
+					os.Exit(1)
+				}
+
+				if learnNoise {
+					if err := config.ValidateNoiseLearning(); err != nil {
+						logrus.Errorf("Error while setting noise learning. %s", err.Error())
+						os.Exit(1)
+					}
+				}
+			}
+
 			if !noiseDetection && (config.IsIgnoreValuesFileSet() || config.IsIgnoreValuesSet()) {
 				logrus.Infof("ignoreValues or ignoreValuesFile attributes are set but noise detection is disabled, so they are going to be ignored.")
 			}
@@ -99,27 +230,53 @@ func main() {
 			config.SetServiceName(serviceName)
 
 			log.Initialize(logLevel)
-			core.StartProxy(&config)
+			if err := core.StartProxy(&config); err != nil {
+				logrus.Errorf("Error while starting Diferencia. %s", err.Error())
+				os.Exit(1)
+			}
 		},
 	}
 
 	cmdStart.Flags().IntVar(&port, "port", 8080, "Listening port of Diferencia proxy")
 	cmdStart.Flags().StringVar(&serviceName, "serviceName", "", "Sets service name under test. By default it takes candidate hostname")
 	cmdStart.Flags().StringVarP(&primaryURL, "primary", "p", "", "Primary Service URL")
-	cmdStart.Flags().StringVarP(&secondaryURL, "secondary", "s", "", "Secondary Service URL")
+	cmdStart.Flags().StringVarP(&secondaryURL, "secondary", "s", "", "Secondary Service URL. Several URLs can be set separated by commas to detect noise with all of them.")
+	cmdStart.Flags().IntVar(&secondaryQuorum, "secondaryQuorum", 0, "Minimum number of secondaries that must be available to detect noise. By default the majority of secondaries.")
 	cmdStart.Flags().StringVarP(&candidateURL, "candidate", "c", "", "Candidate Service URL")
-	cmdStart.Flags().StringVarP(&difference, "difference", "d", "Strict", "Difference mode to compare JSONs")
+	cmdStart.Flags().StringVarP(&difference, "difference", "d", "Strict", "Difference mode to compare JSONs (Strict, Subset, Schema, JsonSchema)")
 	cmdStart.Flags().BoolVarP(&allowUnsafeOperations, "unsafe", "u", false, "Allow none safe operations like PUT, POST, PATCH, ...")
 	cmdStart.Flags().BoolVarP(&noiseDetection, "noisedetection", "n", false, "Enable noise detection. Secondary URL must be provided.")
+	cmdStart.Flags().StringVar(&noiseMode, "noiseMode", "Strict", "Noise detection mode of JSON documents. Strict fails if primary and secondary differ in something apart from values, Tolerant considers added or removed elements as noise.")
+	cmdStart.Flags().BoolVar(&learnNoise, "learnNoise", false, "Learn noise per endpoint so secondaries are only called from time to time once noise is stable. Only valid for JSON documents.")
+	cmdStart.Flags().IntVar(&noiseStableDetections, "noiseStableDetections", 5, "Number of consecutive noise detections without new noise to consider learned noise of an endpoint stable.")
+	cmdStart.Flags().IntVar(&noiseRecheckInterval, "noiseRecheckInterval", 10, "Once learned noise is stable, secondaries are called once every this number of requests.")
 	cmdStart.Flags().StringVar(&storeResults, "storeResults", "", "Directory where output is set. If not specified then nothing is stored. Useful for local development.")
 
 	cmdStart.Flags().StringVarP(&logLevel, "logLevel", "l", "error", "Set log level")
 
 	cmdStart.Flags().BoolVar(&headers, "headers", false, "Enable Http headers comparision")
+	cmdStart.Flags().BoolVar(&compareContentEncoding, "compareContentEncoding", false, "Report different Content-Encoding as a headers difference even if headers comparision is disabled")
+	cmdStart.Flags().IntVar(&maxDecodedBodySize, "maxDecodedBodySize", core.DefaultMaxDecodedBodySize, "Maximum size in megabytes of compressed responses once they are decoded. Larger responses are not compared.")
 	cmdStart.Flags().StringSliceVar(&ignoreHeadersValues, "ignoreHeadersValues", nil, "List of headers key where their value must be ignored for comparision purposes.")
 
-	cmdStart.Flags().StringSliceVar(&ignoreValuesOf, "ignoreValues", nil, "List of JSON Pointers of values that must be ignored for comparision purposes.")
+	cmdStart.Flags().StringArrayVar(&ignoreValuesOf, "ignoreValues", nil, "JSON Pointers separated by commas or JSONPath expressions of values that must be ignored for comparision purposes. JSONPath expressions are not split, so they can contain commas. This flag can be repeated.")
+	cmdStart.Flags().StringSliceVar(&ignoreXPaths, "ignoreXPaths", nil, "List of XPath expressions of XML nodes that must be ignored for comparision purposes.")
+	cmdStart.Flags().StringArrayVar(&ignoreSelectors, "ignoreSelectors", nil, "CSS selectors of HTML elements that must be ignored for comparision purposes. Append @attribute or ::text to ignore only an attribute or the text. This flag can be repeated.")
 	cmdStart.Flags().StringVar(&ignoreValuesFile, "ignoreValuesFile", "", "File location where each line is a JSON pointers definition for ignoring values.")
+	cmdStart.Flags().StringArrayVar(&ignoreTextPatterns, "ignoreTextPatterns", nil, "Regular expressions of texts in plain text bodies and JSON string values that must be ignored for comparision purposes. This flag can be repeated.")
+	cmdStart.Flags().StringVar(&ignoreTextPatternsFile, "ignoreTextPatternsFile", "", "File location where each line is a regular expression of texts that must be ignored.")
+
+	cmdStart.Flags().StringSliceVar(&unorderedArrays, "unorderedArrays", nil, "List of JSON Pointers of arrays whose elements order must be ignored for comparision purposes. * can be used to match any key or index.")
+
+	cmdStart.Flags().StringSliceVar(&arrayKeys, "arrayKeys", nil, "List of JSON Pointers of arrays of objects and the field used to match their elements in the form of pointer=field. * can be used to match any key or index.")
+
+	cmdStart.Flags().StringSliceVar(&numericTolerances, "numericTolerances", nil, "List of JSON Pointers of numbers and their tolerance in the form of pointer=tolerance. Tolerance can be absolute (0.01) or relative (1%). * can be used to match any key or index.")
+	cmdStart.Flags().StringVar(&defaultTolerance, "defaultTolerance", "", "Tolerance applied to all numbers not set in numericTolerances. It can be absolute (0.01) or relative (1%).")
+
+	cmdStart.Flags().StringArrayVar(&valueMatchers, "valueMatchers", nil, "JSON Pointers or JSONPath expressions of values and the matcher that candidate values must match instead of being equal to primary values in the form of pointer=matcher. Matchers are uuid, iso8601, integer, nonNegativeInteger, number, string, boolean, notNull, notEmpty and regex:expression. * can be used to match any key or index. This flag can be repeated.")
+
+	cmdStart.Flags().StringVar(&jsonSchema, "jsonSchema", "", "JSON Schema file location used to validate every candidate response.")
+	cmdStart.Flags().StringSliceVar(&jsonSchemas, "jsonSchemas", nil, "List of JSON Schema file locations per endpoint in the form of [METHOD ]pathPattern=schemaLocation. It has precedence over jsonSchema.")
 
 	cmdStart.Flags().BoolVar(&prometheus, "prometheus", false, "Enable Prometheus endpoint")
 	cmdStart.Flags().IntVar(&prometheusPort, "prometheusPort", 8081, "Prometheus port")
@@ -131,15 +288,24 @@ func main() {
 	cmdStart.Flags().StringVar(&clientCert, "clientCert", "", "Client Certificate path (X509)")
 	cmdStart.Flags().StringVar(&clientKey, "clientKey", "", "Client Key path (X509)")
 
+	cmdStart.Flags().StringSliceVar(&comparators, "comparators", nil, "List of comparators used for media types in the form of mediaType=comparator (Json, Xml, Html, Yaml, Form, Plain, Binary). Media type can be a wildcard (text/*) or a suffix (+json).")
+
 	cmdStart.Flags().BoolVar(&forcePlainText, "forcePlainText", false, "Force the received of content type as plain text instead of json")
 	cmdStart.Flags().IntVar(&levenshteinPercentage, "levenshteinPercentage", 100, "Sets the minimum percentage to be equal in case of using plain text (40, 79, 90, ...)")
+	cmdStart.Flags().StringVar(&textSimilarity, "textSimilarity", "", "Sets the algorithm and the minimum percentage to be equal in case of using plain text in the form of algorithm=percentage. Algorithm can be Levenshtein, WordLevenshtein, Jaccard, Cosine or Lcs. It has precedence over levenshteinPercentage.")
 
 	cmdStart.Flags().BoolVarP(&mirroring, "mirroring", "m", false, "Starts Diferencia in mirroring mode which means that the output provided is the one provided by primary")
+	cmdStart.Flags().StringVar(&bodyDiffFormat, "bodyDiffFormat", "All", "Format of body differences of JSON documents. Text, Patch (JSON Patch operations) or All.")
 	cmdStart.Flags().BoolVar(&returnResult, "returnResult", false, "Set Diferencia to return all avalable information about the current comparision and not only the http status code.")
 	cmdStart.MarkFlagRequired("primary")
 	cmdStart.MarkFlagRequired("candidate")
 
-	rootCmd.AddCommand(cmdStart)
+	return cmdStart
+}
+
+func main() {
+
+	rootCmd.AddCommand(newStartCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		logrus.Errorf(err.Error())
@@ -0,0 +1,33 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Start command", func() {
+
+	Describe("Parsing flags", func() {
+		It("should not split ignored values with commas or quotes", func() {
+			cmdStart := newStartCommand()
+
+			err := cmdStart.ParseFlags([]string{"--ignoreValues", "$.a['b,c']", "--ignoreValues", `$.orders[?(@.status=="PENDING, LATE")].eta`})
+			Expect(err).Should(Succeed())
+
+			ignoreValues, err := cmdStart.Flags().GetStringArray("ignoreValues")
+			Expect(err).Should(Succeed())
+			Expect(ignoreValues).Should(Equal([]string{"$.a['b,c']", `$.orders[?(@.status=="PENDING, LATE")].eta`}))
+		})
+
+		It("should split ignored JSON Pointers separated by commas", func() {
+			cmdStart := newStartCommand()
+
+			err := cmdStart.ParseFlags([]string{"--ignoreValues", "/a,/b", "--ignoreValues", "/c", "--ignoreValues", "$.a['b,c']"})
+			Expect(err).Should(Succeed())
+
+			ignoreValues, err := cmdStart.Flags().GetStringArray("ignoreValues")
+			Expect(err).Should(Succeed())
+			Expect(splitIgnoreValues(ignoreValues)).Should(Equal([]string{"/a", "/b", "/c", "$.a['b,c']"}))
+		})
+	})
+})